@@ -0,0 +1,73 @@
+package obreron
+
+import "fmt"
+
+// Case es el builder de una expresión CASE. Puede usarse como columna, como condición o en ORDER BY con OrderByExpr,
+// y los parámetros de sus ramas se agregan a la consulta en el orden en que aparecen
+//
+//	estado := NewCase().When("vdt.estado = ?", "IFNULL(vdt.num_doc, ?)", 0, "").Else("?", "nulo")
+//	b.Select("id").AddColumn(estado, "num_doc").From("documento", "vdt")
+type Case struct {
+	operand SQLBuilder
+	whens   SQLBuilder
+	els     SQLBuilder
+}
+
+// NewCase devuelve una expresión CASE buscada, `CASE WHEN cond THEN ... END`, en que cada rama tiene su condición
+func NewCase() *Case {
+	return &Case{}
+}
+
+// NewCaseOf devuelve una expresión CASE simple, `CASE operand WHEN value THEN ... END`, en que cada rama
+// compara operand con un valor. params son los parámetros de operand
+func NewCaseOf(operand string, params ...interface{}) *Case {
+	c := &Case{}
+	c.operand.WriteString(operand)
+	c.operand.AddParam(params...)
+	return c
+}
+
+// When agrega la rama `WHEN when THEN then`. when es la condición, o el valor a comparar en un CASE simple.
+// params son los parámetros de when y then, en ese orden
+func (c *Case) When(when, then string, params ...interface{}) *Case {
+	c.whens.WriteString(" WHEN ")
+	c.whens.WriteString(when)
+	c.whens.WriteString(" THEN ")
+	c.whens.WriteString(then)
+	c.whens.AddParam(params...)
+	return c
+}
+
+// Else define el valor v, con sus parámetros params, que toma la expresión si ninguna rama se cumple
+func (c *Case) Else(v string, params ...interface{}) *Case {
+	c.els.Reset()
+	c.els.params = nil
+
+	c.els.WriteString(" ELSE ")
+	c.els.WriteString(v)
+	c.els.AddParam(params...)
+	return c
+}
+
+// ToSQL escribe la expresión en sb, junto con sus parámetros. Devuelve ErrInvalidExpression si no tiene ramas
+func (c *Case) ToSQL(d Dialect, sb *SQLBuilder) error {
+	if c.whens.Len() == 0 {
+		return fmt.Errorf("%w: CASE sin WHEN", ErrInvalidExpression)
+	}
+
+	sb.WriteString("CASE")
+
+	if c.operand.Len() > 0 {
+		sb.WriteByte(' ')
+		sb.Write(c.operand.Bytes())
+		sb.AddParam(c.operand.params...)
+	}
+
+	sb.Write(c.whens.Bytes())
+	sb.AddParam(c.whens.params...)
+	sb.Write(c.els.Bytes())
+	sb.AddParam(c.els.params...)
+
+	sb.WriteString(" END")
+	return nil
+}
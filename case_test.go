@@ -0,0 +1,38 @@
+package obreron
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCase(t *testing.T) {
+	numDoc := NewCase().When("vdt.estado = ?", "IFNULL(vdt.num_doc, ?)", 0, "").Else("?", "nulo")
+	priority := NewCaseOf("d.status").When("?", "1", "open").When("'closed'", "2").Else("3")
+
+	b := NewMaryBuilder().Select("d.id").
+		AddColumn(numDoc, "num_doc").
+		From("documento", "d").
+		Where().AndParam("d.id_sucursal", "=", 126).
+		AndParam(NewCase().When("d.kind = ?", "d.total", "sale").Else("0"), ">", 100).
+		OrderByExpr(priority)
+
+	expected := "SELECT d.id,CASE WHEN vdt.estado = ? THEN IFNULL(vdt.num_doc, ?) ELSE ? END AS num_doc  FROM documento d  WHERE 1=1  AND d.id_sucursal = ? AND CASE WHEN d.kind = ? THEN d.total ELSE 0 END > ? ORDER BY CASE d.status WHEN ? THEN 1 WHEN 'closed' THEN 2 ELSE 3 END "
+
+	q, params := b.Build()
+
+	if q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+
+	if expected := []interface{}{0, "", "nulo", 126, "sale", 100, "open"}; !reflect.DeepEqual(params, expected) {
+		t.Logf("expected params %v, got %v", expected, params)
+		t.FailNow()
+	}
+
+	if q := NewMaryBuilder().Select(NewCase().When("a > b", "a")).String(); q != "SELECT CASE WHEN a > b THEN a END" {
+		t.Logf("unexpected CASE without ELSE: %s", q)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,95 @@
+package obreron
+
+import (
+	"reflect"
+	"sync"
+)
+
+// columnsKey identifica una lista de columnas ya calculada
+type columnsKey struct {
+	t     reflect.Type
+	alias string
+}
+
+// columnLists guarda las listas de columnas ya calculadas por tipo y alias
+var columnLists sync.Map
+
+// Columns devuelve las columnas mapeadas del struct T, según sus tags `db`, listas para pasarse a
+// Select o AddColumns. Si alias no es vacío cada columna se prefija con `alias.`.
+// Los campos con tag `db:"-"` se omiten y los structs embebidos se aplanan, igual que al escanear con ScanAll.
+// La metadata se calcula una sola vez por tipo y alias.
+//
+//	b.Select(obreron.Columns[User]("u")...).From("users", "u")
+func Columns[T any](alias string) []interface{} {
+	return columnsOf(reflect.TypeOf((*T)(nil)).Elem(), alias)
+}
+
+// ColumnsOf es como Columns pero toma el tipo desde el valor v, que puede ser un struct o un puntero a struct
+func ColumnsOf(v interface{}, alias string) []interface{} {
+	return columnsOf(reflect.TypeOf(v), alias)
+}
+
+// ColumnNames devuelve las columnas mapeadas del struct T como strings, ver Columns
+func ColumnNames[T any](alias string) []string {
+	cols := cachedColumns(reflect.TypeOf((*T)(nil)).Elem(), alias)
+
+	out := make([]string, len(cols))
+	for i := range cols {
+		out[i] = cols[i].(string)
+	}
+
+	return out
+}
+
+// columnsOf devuelve una copia de la lista de columnas del tipo t con alias
+func columnsOf(t reflect.Type, alias string) []interface{} {
+	cols := cachedColumns(t, alias)
+	if cols == nil {
+		return nil
+	}
+
+	out := make([]interface{}, len(cols))
+	copy(out, cols)
+
+	return out
+}
+
+// cachedColumns devuelve la lista compartida de columnas del tipo t con alias. No debe modificarse
+func cachedColumns(t reflect.Type, alias string) []interface{} {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	key := columnsKey{t: t, alias: alias}
+
+	if cols, ok := columnLists.Load(key); ok {
+		return cols.([]interface{})
+	}
+
+	si := getStructInfo(t)
+	cols := make([]interface{}, len(si.fields))
+
+	for i, f := range si.fields {
+		if alias != "" {
+			cols[i] = alias + "." + f.column
+		} else {
+			cols[i] = f.column
+		}
+	}
+
+	actual, _ := columnLists.LoadOrStore(key, cols)
+	return actual.([]interface{})
+}
+
+// AddColumns agrega las columnas cs a las ya definidas, sin alias. Cada columna puede ser string u otro SQLBuilder.
+// Combínelo con Columns para agregar las columnas de un struct
+func (s *Select) AddColumns(cs ...interface{}) *Select {
+	for _, c := range cs {
+		s.AddColumn(c, "")
+	}
+	return s
+}
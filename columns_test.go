@@ -0,0 +1,70 @@
+package obreron
+
+import (
+	"reflect"
+	"testing"
+)
+
+type columnsBase struct {
+	ID int64 `db:"user_id"`
+}
+
+type columnsUser struct {
+	columnsBase
+
+	Name     string `db:"user_name"`
+	Mail     string
+	Password string `db:"-"`
+	hidden   string
+}
+
+func TestColumns(t *testing.T) {
+	b := NewMaryBuilder()
+
+	q := b.Select(Columns[columnsUser]("u")...).From("users", "u").String()
+	expected := "SELECT u.user_id,u.user_name,u.mail FROM users u "
+
+	if q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+
+	b2 := NewMaryBuilder()
+
+	q = b2.Select("COUNT(*) AS total").AddColumns(ColumnsOf(&columnsUser{}, "")...).From("users", "").String()
+	expected = "SELECT COUNT(*) AS total,user_id,user_name,mail FROM users"
+
+	if q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+
+	if names := ColumnNames[columnsUser](""); !reflect.DeepEqual(names, []string{"user_id", "user_name", "mail"}) {
+		t.Logf("unexpected names %v", names)
+		t.FailNow()
+	}
+}
+
+func TestColumnsReturnsCopies(t *testing.T) {
+	cols := Columns[columnsUser]("u")
+	cols[0] = "tampered"
+
+	if again := Columns[columnsUser]("u"); again[0] != "u.user_id" {
+		t.Logf("cached columns were modified: %v", again)
+		t.FailNow()
+	}
+
+	if cols := ColumnsOf(10, ""); cols != nil {
+		t.Logf("expected no columns for non struct types, got %v", cols)
+		t.FailNow()
+	}
+}
+
+func BenchmarkColumns(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Columns[columnsUser]("u")
+	}
+}
@@ -0,0 +1,192 @@
+package obreron
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// CommentPlacement indica dónde se ubican los comentarios de una consulta
+type CommentPlacement int8
+
+const (
+	// CommentSuffix ubica los comentarios al final de la consulta, como recomienda sqlcommenter
+	CommentSuffix = CommentPlacement(0)
+
+	// CommentPrefix ubica los comentarios al inicio de la consulta, de modo que no se pierdan si el log de
+	// consultas lentas trunca las consultas largas
+	CommentPrefix = CommentPlacement(1)
+)
+
+// escapeComment evita que el texto de un comentario lo cierre antes de tiempo o abra uno anidado, separando
+// con un espacio cada `*` y `/` contiguos. Se revisa el texto ya escapado, así que el resultado nunca contiene
+// `*/` ni `/*`, aunque se traslapen como en `/*/`
+func escapeComment(text string) string {
+	if !strings.Contains(text, "*/") && !strings.Contains(text, "/*") {
+		return text
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(text) + 4)
+
+	var prev byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if (prev == '*' && c == '/') || (prev == '/' && c == '*') {
+			sb.WriteByte(' ')
+		}
+		sb.WriteByte(c)
+		prev = c
+	}
+
+	return sb.String()
+}
+
+// Comment agrega el comentario text a la consulta, como `/* text */`. Si text contiene `*/` se escapa
+// para que no cierre el comentario
+func (s *Select) Comment(text string) *Select {
+	s.touch()
+	s.comments = append(s.comments, text)
+	return s
+}
+
+// Tag agrega la etiqueta key con el valor value a la consulta. Las etiquetas se escriben en un solo
+// comentario con el formato de sqlcommenter, `/* key='value',... */`, ordenadas por llave y con las llaves
+// y valores codificados como url, por lo que no pueden cerrar el comentario
+func (s *Select) Tag(key, value string) *Select {
+	s.touch()
+
+	if s.tags == nil {
+		s.tags = map[string]string{}
+	}
+
+	s.tags[key] = value
+	return s
+}
+
+// PlaceComments establece dónde se ubican los comentarios y etiquetas de la consulta. Por defecto es CommentSuffix
+func (s *Select) PlaceComments(p CommentPlacement) *Select {
+	s.touch()
+	s.commentPlacement = p
+	return s
+}
+
+type tagsKey struct{}
+
+// WithTags devuelve un contexto con las etiquetas tags, además de las que ya tuviera ctx. Las etiquetas
+// del contexto se agregan a las sentencias ejecutadas con él, o construidas con BuildContext, y las de la
+// sentencia tienen prioridad sobre ellas. Sirven para datos de la petición, como la ruta o el trace id
+func WithTags(ctx context.Context, tags map[string]string) context.Context {
+	merged := make(map[string]string, len(tags))
+
+	for k, v := range ContextTags(ctx) {
+		merged[k] = v
+	}
+
+	for k, v := range tags {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, tagsKey{}, merged)
+}
+
+// ContextTags devuelve las etiquetas agregadas a ctx con WithTags
+func ContextTags(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(tagsKey{}).(map[string]string)
+	return tags
+}
+
+// writeComments escribe los comentarios del Select en su buffer
+func (s *Select) writeComments() {
+	for _, c := range s.comments {
+		s.WriteString("/* ")
+		s.WriteString(escapeComment(c))
+		s.WriteString(" */ ")
+	}
+
+	if len(s.tags) > 0 {
+		s.WriteString(renderTags(s.tags))
+		s.WriteByte(' ')
+	}
+}
+
+// withTags agrega las etiquetas tags al sql q construido por el Select, en el mismo comentario que las suyas
+func (s *Select) withTags(q string, tags map[string]string) string {
+	merged := make(map[string]string, len(tags)+len(s.tags))
+
+	for k, v := range tags {
+		merged[k] = v
+	}
+
+	for k, v := range s.tags {
+		merged[k] = v
+	}
+
+	// se quita el comentario con las etiquetas propias, que se reemplaza por el de todas
+	if len(s.tags) > 0 {
+		own := renderTags(s.tags) + " "
+
+		if s.commentPlacement == CommentPrefix {
+			q = strings.Replace(q, own, "", 1)
+		} else {
+			q = strings.TrimSuffix(q, own)
+		}
+	}
+
+	if s.commentPlacement == CommentPrefix {
+		return renderTags(merged) + " " + q
+	}
+
+	if !strings.HasSuffix(q, " ") {
+		q += " "
+	}
+
+	return q + renderTags(merged)
+}
+
+// addContextTags agrega al sql q del builder b las etiquetas de ctx, si las tiene
+func addContextTags(ctx context.Context, b Builder, q string) string {
+	tags := ContextTags(ctx)
+	if len(tags) == 0 {
+		return q
+	}
+
+	if tb, ok := b.(interface {
+		withTags(q string, tags map[string]string) string
+	}); ok {
+		return tb.withTags(q, tags)
+	}
+
+	return q + " " + renderTags(tags)
+}
+
+// renderTags escribe tags como un comentario de sqlcommenter
+func renderTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString("/* ")
+
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(tagEscape(k))
+		sb.WriteString("='")
+		sb.WriteString(tagEscape(tags[k]))
+		sb.WriteByte('\'')
+	}
+
+	sb.WriteString(" */")
+	return sb.String()
+}
+
+// tagEscape codifica s como url, con los espacios como %20. Así s no contiene `'`, `*` ni `/`
+func tagEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
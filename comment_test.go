@@ -0,0 +1,93 @@
+package obreron
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCommentAndTags(t *testing.T) {
+	b := NewMaryBuilder().Select("id").From("users", "u").Limit(1).
+		Comment("lista de usuarios */ DROP TABLE users; /*").
+		Tag("route", "/api/users/{id}").
+		Tag("controller", "user's")
+
+	expected := "SELECT id FROM users u  LIMIT 1 /* lista de usuarios * / DROP TABLE users; / * */ /* controller='user%27s',route='%2Fapi%2Fusers%2F%7Bid%7D' */ "
+
+	if q := b.String(); q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+
+	expected = "/* lista de usuarios * / DROP TABLE users; / * */ /* controller='user%27s',route='%2Fapi%2Fusers%2F%7Bid%7D' */ SELECT id FROM users u  LIMIT 1 "
+
+	if q := b.PlaceComments(CommentPrefix).String(); q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+
+	if n := len(tokenize(b.String(), true)); n != 9 {
+		t.Logf("expected the comments to be closed where they should, got %d tokens", n)
+		t.FailNow()
+	}
+}
+
+func TestCommentCannotBeClosed(t *testing.T) {
+	cases := map[string]string{
+		"/*/ ; DROP TABLE users; -- ": "SELECT id FROM users /* / * / ; DROP TABLE users; --  */ ",
+		"*/*/":                        "SELECT id FROM users /* * / * / */ ",
+		"**//":                        "SELECT id FROM users /* ** // */ ",
+		"a */ b":                      "SELECT id FROM users /* a * / b */ ",
+	}
+
+	for text, expected := range cases {
+		q := NewMaryBuilder().Select("id").From("users", "").Comment(text).String()
+
+		if q != expected {
+			t.Logf("expected : %s", expected)
+			t.Logf("generated: %s", q)
+			t.FailNow()
+		}
+
+		// todo el texto debe quedar dentro de un solo comentario
+		if toks := tokenize(q, true); toks[len(toks)-1].kind != tokComment || strings.Count(q, "*/") != 1 {
+			t.Logf("the comment %q escaped its delimiters: %s", text, q)
+			t.FailNow()
+		}
+	}
+}
+
+func TestContextTags(t *testing.T) {
+	ctx := WithTags(context.Background(), map[string]string{"traceparent": "00-abc-01", "route": "/ctx"})
+	ctx = WithTags(ctx, map[string]string{"controller": "users"})
+
+	cases := []struct {
+		b        Builder
+		expected string
+	}{
+		{
+			NewMaryBuilder().Select("id").From("users", "").Tag("route", "/own"),
+			"SELECT id FROM users /* controller='users',route='%2Fown',traceparent='00-abc-01' */",
+		},
+		{
+			NewMaryBuilder().Select("id").From("users", "").PlaceComments(CommentPrefix).Comment("c"),
+			"/* controller='users',route='%2Fctx',traceparent='00-abc-01' */ /* c */ SELECT id FROM users",
+		},
+		{
+			NewMaryUpdate("users").Set("name", "ana"),
+			"UPDATE users SET name = ? /* controller='users',route='%2Fctx',traceparent='00-abc-01' */",
+		},
+	}
+
+	for _, c := range cases {
+		q, _, err := BuildContext(ctx, c.b)
+
+		if err != nil || q != c.expected {
+			t.Logf("expected : %s", c.expected)
+			t.Logf("generated: %s %v", q, err)
+			t.FailNow()
+		}
+	}
+}
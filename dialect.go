@@ -1,11 +1,39 @@
 package obreron
 
-import "fmt"
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
 
 var (
 	_ Dialect = Mysql{}
+	_ Dialect = Postgres{}
+	_ Dialect = Sqlite{}
+
+	_ Rebinder      = Postgres{}
+	_ NullsOrdering = Postgres{}
+	_ NullsOrdering = Sqlite{}
+
+	_ HintSupport      = Mysql{}
+	_ LiteralFormatter = Mysql{}
+	_ LiteralFormatter = Postgres{}
+
+	_ Locker = Mysql{}
+	_ Locker = Postgres{}
+
+	_ DistinctOnSupport = Postgres{}
 )
 
+// ErrInvalidIdentifier es devuelto por QuoteIdent cuando el identificador no puede escaparse de forma segura
+var ErrInvalidIdentifier = errors.New("obreron: identificador inválido")
+
 // Dialect representa el dialecto de la consulta
 type Dialect interface {
 	Quote(v interface{}) string
@@ -14,12 +42,54 @@ type Dialect interface {
 	CloseEnclose() string
 }
 
+var dialects = struct {
+	sync.RWMutex
+	byName map[string]Dialect
+	byType map[reflect.Type]string
+}{
+	byName: map[string]Dialect{"mysql": Mysql{}, "postgres": Postgres{}, "sqlite": Sqlite{}},
+	byType: map[reflect.Type]string{
+		reflect.TypeOf(Mysql{}):    "mysql",
+		reflect.TypeOf(Postgres{}): "postgres",
+		reflect.TypeOf(Sqlite{}):   "sqlite",
+	},
+}
+
+// RegisterDialect registra el dialecto d con el nombre name, para que pueda recuperarse al
+// decodificar consultas serializadas
+func RegisterDialect(name string, d Dialect) {
+	dialects.Lock()
+	defer dialects.Unlock()
+
+	dialects.byName[name] = d
+	dialects.byType[reflect.TypeOf(d)] = name
+}
+
+// LookupDialect devuelve el dialecto registrado con el nombre name
+func LookupDialect(name string) (Dialect, bool) {
+	dialects.RLock()
+	defer dialects.RUnlock()
+
+	d, ok := dialects.byName[name]
+	return d, ok
+}
+
+// DialectName devuelve el nombre con el que se registró el dialecto d, o un string vacio si no está registrado
+func DialectName(d Dialect) string {
+	dialects.RLock()
+	defer dialects.RUnlock()
+
+	return dialects.byType[reflect.TypeOf(d)]
+}
+
 // Mysql es un dialecto que permite construir consultas para mysql y mariadb
 type Mysql struct{}
 
-// Quote escapa a su argumento según el dialecto de la consulta
+// Quote escapa a su argumento según el dialecto de la consulta.
+// Los backticks embebidos se duplican y los nombres calificados como `esquema.tabla`
+// se escapan parte por parte. El comodín `*` se deja tal cual.
 func (m Mysql) Quote(v interface{}) string {
-	return fmt.Sprintf("`%v`", v)
+	return quoteIdentifier(fmt.Sprint(v), '`')
 }
 
 // ParamMark devuelve una marca de parámetro posicional
@@ -36,3 +106,242 @@ func (m Mysql) OpenEnclose() string {
 func (m Mysql) CloseEnclose() string {
 	return ")"
 }
+
+// FormatString devuelve s como literal de texto, escapando con `\` los caracteres especiales
+func (m Mysql) FormatString(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s) + 2)
+	sb.WriteByte('\'')
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case 0:
+			sb.WriteString(`\0`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case 0x1a:
+			sb.WriteString(`\Z`)
+		case '\\', '\'', '"':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	sb.WriteByte('\'')
+	return sb.String()
+}
+
+// FormatBytes devuelve b como literal hexadecimal
+func (m Mysql) FormatBytes(b []byte) string {
+	return standardLiterals{}.FormatBytes(b)
+}
+
+// FormatTime devuelve t como literal DATETIME, sin zona horaria
+func (m Mysql) FormatTime(t time.Time) string {
+	return "'" + t.Format("2006-01-02 15:04:05.999999") + "'"
+}
+
+// SupportsHints indica que mysql soporta pistas de índice, del optimizador y modificadores de SELECT
+func (m Mysql) SupportsHints() bool {
+	return true
+}
+
+// SupportsLock indica que mysql soporta FOR UPDATE, FOR SHARE desde la versión 8 y LOCK IN SHARE MODE
+func (m Mysql) SupportsLock(strength LockStrength) bool {
+	switch strength {
+	case LockUpdate, LockShare, LockInShareMode:
+		return true
+	}
+	return false
+}
+
+// Postgres es un dialecto que permite construir consultas para postgresql.
+// Las consultas se construyen con marcas `?` que se convierten a `$1`, `$2`... al ejecutarlas, ver Rebind
+type Postgres struct{}
+
+// Quote escapa a su argumento con comillas dobles, duplicando las comillas embebidas
+// y escapando parte por parte los nombres calificados
+func (p Postgres) Quote(v interface{}) string {
+	return quoteIdentifier(fmt.Sprint(v), '"')
+}
+
+// ParamMark devuelve una marca de parámetro posicional
+func (p Postgres) ParamMark() string {
+	return "?"
+}
+
+// OpenEnclose Agrega un abre parentesis ( la consulta
+func (p Postgres) OpenEnclose() string {
+	return "("
+}
+
+// CloseEnclose Agrega un cierre de parentesis ) la consulta
+func (p Postgres) CloseEnclose() string {
+	return ")"
+}
+
+// Rebind convierte las marcas `?` de q en marcas numeradas `$1`, `$2`...
+func (p Postgres) Rebind(q string) string {
+	pos := placeholders(q, false)
+	if len(pos) == 0 {
+		return q
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(q) + len(pos)*2)
+
+	last := 0
+	for i, at := range pos {
+		sb.WriteString(q[last:at])
+		sb.WriteByte('$')
+		sb.WriteString(strconv.Itoa(i + 1))
+		last = at + 1
+	}
+	sb.WriteString(q[last:])
+
+	return sb.String()
+}
+
+// SupportsNullsOrder indica que postgresql soporta NULLS FIRST y NULLS LAST
+func (p Postgres) SupportsNullsOrder() bool {
+	return true
+}
+
+// FormatString devuelve s como literal de texto, duplicando las comillas simples
+func (p Postgres) FormatString(s string) string {
+	return standardLiterals{}.FormatString(s)
+}
+
+// FormatBytes devuelve b como literal bytea en formato hexadecimal
+func (p Postgres) FormatBytes(b []byte) string {
+	return `'\x` + hex.EncodeToString(b) + "'"
+}
+
+// FormatTime devuelve t como literal de fecha con zona horaria
+func (p Postgres) FormatTime(t time.Time) string {
+	return "'" + t.Format("2006-01-02 15:04:05.999999-07:00") + "'"
+}
+
+// SupportsDistinctOn indica que postgresql soporta DISTINCT ON
+func (p Postgres) SupportsDistinctOn() bool {
+	return true
+}
+
+// SupportsLock indica que postgresql soporta todos los bloqueos de filas salvo el LOCK IN SHARE MODE de mysql
+func (p Postgres) SupportsLock(strength LockStrength) bool {
+	switch strength {
+	case LockUpdate, LockShare, LockNoKeyUpdate, LockKeyShare:
+		return true
+	}
+	return false
+}
+
+// Sqlite es un dialecto que permite construir consultas para sqlite
+type Sqlite struct{}
+
+// Quote escapa a su argumento con comillas dobles, duplicando las comillas embebidas
+// y escapando parte por parte los nombres calificados
+func (l Sqlite) Quote(v interface{}) string {
+	return quoteIdentifier(fmt.Sprint(v), '"')
+}
+
+// ParamMark devuelve una marca de parámetro posicional
+func (l Sqlite) ParamMark() string {
+	return "?"
+}
+
+// OpenEnclose Agrega un abre parentesis ( la consulta
+func (l Sqlite) OpenEnclose() string {
+	return "("
+}
+
+// CloseEnclose Agrega un cierre de parentesis ) la consulta
+func (l Sqlite) CloseEnclose() string {
+	return ")"
+}
+
+// SupportsNullsOrder indica que sqlite soporta NULLS FIRST y NULLS LAST
+func (l Sqlite) SupportsNullsOrder() bool {
+	return true
+}
+
+// Rebinder es implementada por los dialectos cuyas marcas de parámetro no son `?`
+type Rebinder interface {
+	// Rebind convierte las marcas `?` de q a las del dialecto
+	Rebind(q string) string
+}
+
+// Rebind convierte las marcas de parámetro `?` de q a las del dialecto d, si este las necesita.
+// Úselo cuando ejecute por su cuenta el resultado de Build()
+func Rebind(d Dialect, q string) string {
+	if r, ok := d.(Rebinder); ok {
+		return r.Rebind(q)
+	}
+	return q
+}
+
+// QuoteIdent escapa el identificador ident según el dialecto d, devolviendo un error
+// si contiene caracteres de control o NUL, si está vacío o si alguna de sus partes separadas por puntos lo está.
+// Úselo para identificadores elegidos por el usuario, como columnas de ordenamiento.
+func QuoteIdent(d Dialect, ident string) (string, error) {
+	if ident == "" {
+		return "", fmt.Errorf("%w: identificador vacío", ErrInvalidIdentifier)
+	}
+
+	for _, part := range strings.Split(ident, ".") {
+		if part == "" {
+			return "", fmt.Errorf("%w: %q tiene una parte vacía", ErrInvalidIdentifier, ident)
+		}
+	}
+
+	for _, r := range ident {
+		if r == 0 || unicode.IsControl(r) {
+			return "", fmt.Errorf("%w: %q contiene caracteres de control", ErrInvalidIdentifier, ident)
+		}
+	}
+
+	return d.Quote(ident), nil
+}
+
+// quoteIdentifier escapa cada parte separada por puntos de ident con el caracter q,
+// duplicando las ocurrencias de q dentro de cada parte
+func quoteIdentifier(ident string, q byte) string {
+	var sb strings.Builder
+	sb.Grow(len(ident) + 2)
+
+	for i := 0; ; i++ {
+		part := ident
+		dot := strings.IndexByte(ident, '.')
+		if dot > -1 {
+			part = ident[:dot]
+		}
+
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+
+		if part == "*" {
+			sb.WriteByte('*')
+		} else {
+			sb.WriteByte(q)
+			for j := 0; j < len(part); j++ {
+				if part[j] == q {
+					sb.WriteByte(q)
+				}
+				sb.WriteByte(part[j])
+			}
+			sb.WriteByte(q)
+		}
+
+		if dot == -1 {
+			break
+		}
+		ident = ident[dot+1:]
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,89 @@
+package obreron
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMysqlQuote(t *testing.T) {
+	cases := []struct {
+		in       interface{}
+		expected string
+	}{
+		{"columna", "`columna`"},
+		{"columna con espacios", "`columna con espacios`"},
+		{"col`umna", "`col``umna`"},
+		{"esquema.tabla", "`esquema`.`tabla`"},
+		{"esquema.tabla.*", "`esquema`.`tabla`.*"},
+		{"*", "*"},
+		{"t.`x`", "`t`.```x```"},
+		{10, "`10`"},
+	}
+
+	d := Mysql{}
+
+	for _, c := range cases {
+		if q := d.Quote(c.in); q != c.expected {
+			t.Logf("expected : %s", c.expected)
+			t.Logf("generated: %s", q)
+			t.Fail()
+		}
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	b := NewMaryBuilder()
+
+	q, err := b.QuoteIdent("u.created_at")
+
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	if q != "`u`.`created_at`" {
+		t.Logf("expected : %s", "`u`.`created_at`")
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+
+	for _, in := range []string{"", "nombre\x00", "a\nb", "tab\tla", "a..b", "u.", ".u"} {
+		if _, err := QuoteIdent(Mysql{}, in); !errors.Is(err, ErrInvalidIdentifier) {
+			t.Logf("expected ErrInvalidIdentifier for %q, got %v", in, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestPostgresRebind(t *testing.T) {
+	q := `SELECT "a?" FROM t WHERE a = ? AND b = 'it''s ?' AND c = ? -- ¿?
+AND d = ? /* ? */`
+	expected := `SELECT "a?" FROM t WHERE a = $1 AND b = 'it''s ?' AND c = $2 -- ¿?
+AND d = $3 /* ? */`
+
+	if r := Rebind(Postgres{}, q); r != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", r)
+		t.FailNow()
+	}
+
+	if r := Rebind(Mysql{}, q); r != q {
+		t.Logf("mysql should not rebind, generated: %s", r)
+		t.FailNow()
+	}
+
+	// en postgresql `#` es un operador, no un comentario
+	q = `SELECT data #>> '{a}' FROM t WHERE flags # ? = ? AND data #> ? IS NOT NULL`
+	expected = `SELECT data #>> '{a}' FROM t WHERE flags # $1 = $2 AND data #> $3 IS NOT NULL`
+
+	if r := Rebind(Postgres{}, q); r != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", r)
+		t.FailNow()
+	}
+
+	if n := len(placeholders("SELECT a # ?\nFROM t WHERE b = ?", true)); n != 1 {
+		t.Logf("expected mysql to skip the # comment, got %d marks", n)
+		t.FailNow()
+	}
+}
@@ -1,6 +1,32 @@
 package main
 
-import "github.com/profe-ajedrez/obreron"
+import (
+	"time"
+
+	"github.com/profe-ajedrez/obreron"
+)
+
+// movimiento es una fila del reporte de movimientos de stock. Sus campos definen las columnas a seleccionar
+type movimiento struct {
+	CreatedAtMs                int64
+	FechaMovimiento            time.Time
+	NombreProducto             string
+	CantidadEntrada            float64
+	CantidadSalida             float64
+	Stock                      float64
+	Costo                      float64
+	CodigoVarianteProducto     string
+	CodigoBarras               string
+	NumDocTributario           string
+	NombreTipoDocumento        string
+	IDVentaDocumentoTributario int64 `db:"id_venta_documento_tributario"`
+	IDDetalleIngresoStock      int64 `db:"id_detalle_ingreso_stock"`
+	IDConsumoStock             int64 `db:"id_consumo_stock"`
+	UsuarioMovimiento          string
+	IDDespacho                 int64 `db:"id_despacho"`
+	UsoDocumento               string
+	NumeroSerie                *string
+}
 
 func main() {
 	bl := obreron.NewMaryBuilder()
@@ -19,22 +45,30 @@ func main() {
 		"dd.costo AS costo",
 		"vp.codigo",
 		"vp.barras",
-		"CASE WHEN vdt.estado = 0 THEN IFNULL(vdt.num_doc, '') ELSE '' END AS num_doc",
-		"CASE WHEN vdt.estado = 0 THEN IFNULL(td.nombre_tipo, '') ELSE 'nulo' END AS nombre_tipo",
-		`CASE WHEN vdt.estado = 0 THEN IFNULL(
-		vdt.id_documento, 
-		0
-		) ELSE -99 END AS id_documento`,
-		"IFNULL(dd.ids_detalle_ingreso, 0) AS id_detalle_ingreso",
-		"0 AS id_consumo",
+	).AddColumn(
+		obreron.NewCase().When("vdt.estado = ?", "IFNULL(vdt.num_doc, ?)", 0, "").Else("?", ""), "num_doc",
+	).AddColumn(
+		obreron.NewCase().When("vdt.estado = ?", "IFNULL(td.nombre_tipo, ?)", 0, "").Else("?", "nulo"), "nombre_tipo",
+	).AddColumn(
+		obreron.NewCase().When("vdt.estado = ?", "IFNULL(vdt.id_documento, ?)", 0, 0).Else("?", -99), "id_documento",
+	).AddColumn(
+		"IFNULL(dd.ids_detalle_ingreso, 0)", "id_detalle_ingreso",
+	).AddColumn(
+		"0", "id_consumo",
+	).AddColumn(
 		`CONCAT(
 		us.nombre_usuario, ' ', us.apellido_usuario
-		) AS usuario_movimiento`,
-		"d.id_despacho AS id_despacho",
-		"td.uso_documento AS uso_documento",
-		"dd.id_detalle_despacho",
-		"vdt.estado_documento",
-		"dd.numero_serie",
+		)`, "usuario_movimiento",
+	).AddColumn(
+		"d.id_despacho", "id_despacho",
+	).AddColumn(
+		"td.uso_documento", "uso_documento",
+	).AddColumn(
+		"dd.id_detalle_despacho", "",
+	).AddColumn(
+		"vdt.estado_documento", "",
+	).AddColumn(
+		"dd.numero_serie", "",
 	).From(
 		"detalle_desp", "dd",
 	).Inner(
@@ -54,26 +88,7 @@ func main() {
 
 	bl2 := obreron.NewMaryBuilder()
 
-	bl2.Select().From(bl, "out_detail")
-
-	bl2.AddColumn("created_at_ms", "")
-	bl2.AddColumn("fecha_movimiento", "")
-	bl2.AddColumn("nombre_producto", "")
-	bl2.AddColumn("cantidad_entrada", "")
-	bl2.AddColumn("cantidad_salida", "")
-	bl2.AddColumn("stock", "")
-	bl2.AddColumn("costo", "")
-	bl2.AddColumn("codigo_variante_producto", "")
-	bl2.AddColumn("codigo_barras", "")
-	bl2.AddColumn("num_doc_tributario", "")
-	bl2.AddColumn("nombre_tipo_documento", "")
-	bl2.AddColumn("id_venta_documento_tributario", "")
-	bl2.AddColumn("id_detalle_ingreso_stock", "")
-	bl2.AddColumn("id_consumo_stock", "")
-	bl2.AddColumn("usuario_movimiento", "")
-	bl2.AddColumn("id_despacho", "")
-	bl2.AddColumn("uso_documento", "")
-	bl2.AddColumn("numero_serie ", "")
+	bl2.Select(obreron.Columns[movimiento]("")...).From(bl, "out_detail")
 
 	bl2.GroupBy("id_detalle_desp")
 	bl2.OrderBy("id_despacho ASC")
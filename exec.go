@@ -0,0 +1,216 @@
+package obreron
+
+import (
+	"context"
+	"database/sql"
+)
+
+var (
+	_ Runner = (*sql.DB)(nil)
+	_ Runner = (*sql.Tx)(nil)
+	_ Runner = (*sql.Conn)(nil)
+
+	_ Builder = (*Select)(nil)
+	_ Builder = (*Insert)(nil)
+	_ Builder = (*Update)(nil)
+)
+
+// Runner es el conjunto mínimo de métodos de database/sql necesarios para ejecutar una consulta.
+// Es satisfecho por *sql.DB, *sql.Tx y *sql.Conn
+type Runner interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Builder es implementado por los builders que pueden ejecutarse contra un Runner
+type Builder interface {
+	Build() (string, []interface{})
+	Dialect() Dialect
+}
+
+// dialectRunner asocia un dialecto a un Runner
+type dialectRunner struct {
+	Runner
+	dialect Dialect
+}
+
+// Dialect devuelve el dialecto asociado al Runner
+func (r dialectRunner) Dialect() Dialect {
+	return r.dialect
+}
+
+// WithDialect asocia el dialecto d al Runner r, de forma que las marcas de parámetro de las consultas
+// ejecutadas con él se conviertan a las de d, sin importar el dialecto con que se construyeron
+func WithDialect(r Runner, d Dialect) Runner {
+	return dialectRunner{Runner: r, dialect: d}
+}
+
+// QueryError envuelve un error de ejecución junto con el sql que lo produjo
+type QueryError struct {
+	SQL    string
+	Params []interface{}
+	Err    error
+}
+
+func (e *QueryError) Error() string {
+	return "obreron: " + e.Err.Error() + " [" + e.SQL + "]"
+}
+
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// wrapQueryError envuelve err junto al sql q, si err no es nil
+func wrapQueryError(err error, q string, params []interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &QueryError{SQL: q, Params: params, Err: err}
+}
+
+// runnerDialect devuelve el dialecto asociado a r, o d si r no tiene uno
+func runnerDialect(r Runner, d Dialect) Dialect {
+	if dr, ok := r.(interface{ Dialect() Dialect }); ok {
+		return dr.Dialect()
+	}
+	return d
+}
+
+// run construye b para r y lo ejecuta con exec, pasando por los hooks que le aplican
+func run(ctx context.Context, r Runner, b Builder, mode ExecMode, exec func(ctx context.Context, st *Statement) error) (*Statement, error) {
+	st, hooks, err := prepare(ctx, r, b, mode)
+	if err != nil {
+		return st, err
+	}
+
+	if err := around(ctx, st, hooks, exec); err != nil {
+		// un hook pudo fallar después de la ejecución, dejando las filas abiertas
+		if st.Rows != nil {
+			_ = st.Rows.Close()
+			st.Rows = nil
+		}
+		return st, err
+	}
+
+	return st, nil
+}
+
+// queryWith devuelve la ejecución de una consulta con r
+func queryWith(r Runner) func(ctx context.Context, st *Statement) error {
+	return func(ctx context.Context, st *Statement) error {
+		rows, err := r.QueryContext(ctx, st.SQL, st.Params...)
+		if err != nil {
+			return wrapQueryError(err, st.SQL, st.Params)
+		}
+
+		st.Rows = rows
+		return nil
+	}
+}
+
+// rowQuerier es implementada por los Runner que pueden rechazar un QueryRow sin ejecutarlo, como StmtCache,
+// ya que *sql.Row no puede llevar un error propio
+type rowQuerier interface {
+	queryRow(ctx context.Context, q string, args ...interface{}) (*sql.Row, error)
+}
+
+// Row es el resultado de QueryRow. Envuelve a *sql.Row para que los errores incluyan el sql ejecutado
+type Row struct {
+	row    *sql.Row
+	q      string
+	params []interface{}
+	err    error
+}
+
+// Scan copia las columnas de la fila en dest, ver (*sql.Row).Scan.
+// Si no hay filas el error envuelve a sql.ErrNoRows
+func (r *Row) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	return wrapQueryError(r.row.Scan(dest...), r.q, r.params)
+}
+
+// Err devuelve el error de la consulta, si lo hubo, ver (*sql.Row).Err
+func (r *Row) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return wrapQueryError(r.row.Err(), r.q, r.params)
+}
+
+// Query ejecuta la consulta construida por b con r y devuelve sus filas
+func Query(ctx context.Context, r Runner, b Builder) (*sql.Rows, error) {
+	st, err := run(ctx, r, b, ModeQuery, queryWith(r))
+	if err != nil {
+		return nil, err
+	}
+	return st.Rows, nil
+}
+
+// QueryRow ejecuta la consulta construida por b con r, esperando a lo más una fila
+func QueryRow(ctx context.Context, r Runner, b Builder) *Row {
+	st, err := run(ctx, r, b, ModeQueryRow, func(ctx context.Context, st *Statement) error {
+		inner := r
+		if dr, ok := r.(dialectRunner); ok {
+			inner = dr.Runner
+		}
+
+		if rq, ok := inner.(rowQuerier); ok {
+			row, err := rq.queryRow(ctx, st.SQL, st.Params...)
+			if err != nil {
+				return wrapQueryError(err, st.SQL, st.Params)
+			}
+			st.Row = row
+			return nil
+		}
+
+		st.Row = r.QueryRowContext(ctx, st.SQL, st.Params...)
+		return nil
+	})
+
+	if err != nil {
+		return &Row{q: st.SQL, params: st.Params, err: err}
+	}
+
+	return &Row{row: st.Row, q: st.SQL, params: st.Params}
+}
+
+// Exec ejecuta la sentencia construida por b con r sin devolver filas
+func Exec(ctx context.Context, r Runner, b Builder) (sql.Result, error) {
+	st, err := run(ctx, r, b, ModeExec, func(ctx context.Context, st *Statement) error {
+		res, err := r.ExecContext(ctx, st.SQL, st.Params...)
+		if err != nil {
+			return wrapQueryError(err, st.SQL, st.Params)
+		}
+
+		st.Result = res
+		if n, err := res.RowsAffected(); err == nil {
+			st.RowCount = n
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return st.Result, nil
+}
+
+// Query ejecuta la consulta con r y devuelve sus filas
+func (s *Select) Query(ctx context.Context, r Runner) (*sql.Rows, error) {
+	return Query(ctx, r, s)
+}
+
+// QueryRow ejecuta la consulta con r, esperando a lo más una fila
+func (s *Select) QueryRow(ctx context.Context, r Runner) *Row {
+	return QueryRow(ctx, r, s)
+}
+
+// Exec ejecuta la consulta con r sin devolver filas
+func (s *Select) Exec(ctx context.Context, r Runner) (sql.Result, error) {
+	return Exec(ctx, r, s)
+}
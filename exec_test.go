@@ -0,0 +1,256 @@
+package obreron
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeResult es la respuesta que el driver de pruebas entrega para una consulta
+type fakeResult struct {
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+	err      error
+}
+
+// fakeCall registra una consulta recibida por el driver de pruebas
+type fakeCall struct {
+	query string
+	args  []driver.Value
+}
+
+// fakeDB guarda las respuestas y el registro de llamadas de una base de datos del driver de pruebas
+type fakeDB struct {
+	mu       sync.Mutex
+	results  map[string]fakeResult
+	calls    []fakeCall
+	prepares int
+	closes   int
+}
+
+func (db *fakeDB) respond(q string, r fakeResult) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.results[q] = r
+}
+
+func (db *fakeDB) lastCall() fakeCall {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if len(db.calls) == 0 {
+		return fakeCall{}
+	}
+	return db.calls[len(db.calls)-1]
+}
+
+var (
+	fakeDBs   sync.Map
+	fakeDBSeq int64
+)
+
+func init() {
+	sql.Register("obreronfake", fakeDriver{})
+}
+
+// newFakeDB abre una base de datos nueva del driver de pruebas
+func newFakeDB(t testing.TB) (*sql.DB, *fakeDB) {
+	dsn := fmt.Sprintf("fake-%d", atomic.AddInt64(&fakeDBSeq, 1))
+	fdb := &fakeDB{results: map[string]fakeResult{}}
+	fakeDBs.Store(dsn, fdb)
+
+	db, err := sql.Open("obreronfake", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db, fdb
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	fdb, ok := fakeDBs.Load(dsn)
+	if !ok {
+		return nil, errors.New("fake: dsn desconocido")
+	}
+	return &fakeConn{db: fdb.(*fakeDB)}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(q string) (driver.Stmt, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.prepares++
+	return &fakeStmt{db: c.db, q: q}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	db *fakeDB
+	q  string
+}
+
+func (s *fakeStmt) Close() error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.closes++
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) result(args []driver.Value) fakeResult {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.calls = append(s.db.calls, fakeCall{query: s.q, args: args})
+	return s.db.results[s.q]
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	r := s.result(args)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return driver.RowsAffected(r.affected), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	r := s.result(args)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &fakeRows{columns: r.columns, rows: r.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	i       int
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.i])
+	r.i++
+	return nil
+}
+
+func TestSelectQuery(t *testing.T) {
+	db, fdb := newFakeDB(t)
+
+	b := NewMaryBuilder().Select("user_id", "user_name").From("users", "u").Where().AndParam("user_status", "=", 1)
+	q, _ := b.Build()
+
+	fdb.respond(q, fakeResult{
+		columns: []string{"user_id", "user_name"},
+		rows:    [][]driver.Value{{int64(1), "mary"}, {int64(2), "ann"}},
+	})
+
+	rows, err := b.Query(context.Background(), db)
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var id int64
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+
+	if !reflect.DeepEqual(names, []string{"mary", "ann"}) {
+		t.Logf("unexpected rows %v", names)
+		t.FailNow()
+	}
+
+	if c := fdb.lastCall(); c.query != q || !reflect.DeepEqual(c.args, []driver.Value{int64(1)}) {
+		t.Logf("unexpected call %#v", c)
+		t.FailNow()
+	}
+}
+
+func TestQueryRebindsToRunnerDialect(t *testing.T) {
+	db, fdb := newFakeDB(t)
+
+	b := NewMaryBuilder().Select("id").From("jobs", "j").Where().
+		AndParam("status", "=", "pending").
+		And("note <> 'why?'").
+		AndParam("attempts", "<", 3)
+
+	if _, err := b.Exec(context.Background(), WithDialect(db, Postgres{})); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	expected := "SELECT id FROM jobs j  WHERE 1=1  AND status = $1 AND note <> 'why?' AND attempts < $2"
+
+	if c := fdb.lastCall(); c.query != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", c.query)
+		t.FailNow()
+	}
+}
+
+func TestQueryErrorsIncludeSQL(t *testing.T) {
+	db, fdb := newFakeDB(t)
+	boom := errors.New("boom")
+
+	b := NewBuilder(Postgres{}).Select("id").From("jobs", "j").Where().AndParam("id", "=", 7)
+	q, _ := b.Build()
+	rebound := Rebind(Postgres{}, q)
+
+	fdb.respond(rebound, fakeResult{err: boom})
+
+	_, err := b.Query(context.Background(), db)
+
+	var qe *QueryError
+	if !errors.As(err, &qe) || !errors.Is(err, boom) || qe.SQL != rebound || qe.Params[0] != 7 {
+		t.Logf("unexpected error %#v", err)
+		t.FailNow()
+	}
+
+	err = b.QueryRow(context.Background(), db).Scan(new(int))
+	if !errors.As(err, &qe) || !errors.Is(err, boom) {
+		t.Logf("unexpected error %#v", err)
+		t.FailNow()
+	}
+
+	fdb.respond(rebound, fakeResult{columns: []string{"id"}})
+
+	err = b.QueryRow(context.Background(), db).Scan(new(int))
+	if !errors.Is(err, sql.ErrNoRows) || !errors.As(err, &qe) {
+		t.Logf("expected sql.ErrNoRows, got %#v", err)
+		t.FailNow()
+	}
+}
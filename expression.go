@@ -0,0 +1,51 @@
+package obreron
+
+import "errors"
+
+// ErrInvalidExpression indica que una expresión no puede escribirse en la consulta
+var ErrInvalidExpression = errors.New("obreron: expresión inválida")
+
+var (
+	_ Expression = (*Case)(nil)
+	_ Expression = (*WindowFunc)(nil)
+)
+
+// Expression es un fragmento de sql con sus propios parámetros, como Case o una función de ventana. Puede
+// usarse como columna, origen, join o condición, lo que permite agregar expresiones propias, como rutas
+// json o funciones de un motor en particular
+//
+//	type jsonPath struct{ col, path string }
+//
+//	func (j jsonPath) ToSQL(d Dialect, w *SQLBuilder) error {
+//		w.WriteString("JSON_EXTRACT(" + d.Quote(j.col) + ", ?)")
+//		w.AddParam(j.path)
+//		return nil
+//	}
+type Expression interface {
+	// ToSQL escribe la expresión en w según el dialecto d, agregando sus parámetros con w.AddParam en el
+	// orden en que aparecen sus marcas. Si devuelve un error la expresión se descarta y la consulta no se ejecuta
+	ToSQL(d Dialect, w *SQLBuilder) error
+}
+
+// parseExpression escribe la expresión circumstance en subject, registrando su error si falla
+func parseExpression(subject *SQLBuilder, circumstance interface{}, parameter interface{}, opt *parsingOptions) {
+	n, pn := subject.Len(), len(subject.params)
+
+	if err := circumstance.(Expression).ToSQL(subject.Dialect(), subject); err != nil {
+		// se descarta lo que la expresión alcanzó a escribir
+		subject.Truncate(n)
+		subject.params = subject.params[:pn]
+		subject.setErr(err)
+	}
+}
+
+// Err devuelve el primer error registrado al escribir expresiones en el SQLBuilder
+func (sb *SQLBuilder) Err() error {
+	return sb.err
+}
+
+func (sb *SQLBuilder) setErr(err error) {
+	if sb.err == nil {
+		sb.err = err
+	}
+}
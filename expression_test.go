@@ -0,0 +1,109 @@
+package obreron
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type jsonPath struct {
+	col, path string
+}
+
+func (j jsonPath) ToSQL(d Dialect, w *SQLBuilder) error {
+	if j.path == "" {
+		w.WriteString("JSON_EXTRACT(")
+		return errors.New("empty path")
+	}
+
+	w.WriteString("JSON_EXTRACT(" + d.Quote(j.col) + ", ?)")
+	w.AddParam(j.path)
+	return nil
+}
+
+type seriesOf struct{ n int }
+
+func (s seriesOf) ToSQL(d Dialect, w *SQLBuilder) error {
+	w.WriteString("generate_series(1, ?)")
+	w.AddParam(s.n)
+	return nil
+}
+
+func TestExpression(t *testing.T) {
+	b := NewBuilder(Postgres{}).Select("u.id", jsonPath{"u.data", "$.name"}).
+		AddColumn(jsonPath{"u.data", "$.age"}, "age").
+		From("users", "u").
+		Inner(seriesOf{3}, "s", "s = u.level").
+		Where().AndParam(jsonPath{"u.data", "$.active"}, "=", true)
+
+	expected := `SELECT u.id,JSON_EXTRACT("u"."data", ?),JSON_EXTRACT("u"."data", ?) AS age  FROM users u  INNER JOIN generate_series(1, ?) s  ON s = u.level WHERE 1=1  AND JSON_EXTRACT("u"."data", ?) = ?`
+
+	q, params := b.Build()
+
+	if q != expected || b.Err() != nil {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s %v", q, b.Err())
+		t.FailNow()
+	}
+
+	if expected := []interface{}{"$.name", "$.age", 3, "$.active", true}; !reflect.DeepEqual(params, expected) {
+		t.Logf("expected params %v, got %v", expected, params)
+		t.FailNow()
+	}
+}
+
+func TestExpressionErrors(t *testing.T) {
+	b := NewMaryBuilder().Select("id", jsonPath{"data", ""}).From("users", "")
+
+	if b.Err() == nil || b.Err().Error() != "empty path" || b.String() != "SELECT id, FROM users" {
+		t.Logf("expected the failed expression to be dropped and its error kept, got %q %v", b.String(), b.Err())
+		t.FailNow()
+	}
+
+	b.Reset()
+
+	if b.Select("id").From("users", "").Err() != nil {
+		t.Logf("expected Reset to clear the error, got %v", b.Err())
+		t.FailNow()
+	}
+
+	cases := map[string]error{
+		"unsupported type":  NewMaryBuilder().Select("id").From("users", "").Where().AndParam(42, "=", 1).Err(),
+		"case without when": NewMaryBuilder().AddColumn(NewCase().Else("1"), "x").Err(),
+		"update":            NewMaryUpdate("users").Set("a", 1).Where().AndParam(3.5, "=", 1).Err(),
+	}
+
+	for name, err := range cases {
+		if !errors.Is(err, ErrInvalidExpression) {
+			t.Logf("%s: expected %v, got %v", name, ErrInvalidExpression, err)
+			t.FailNow()
+		}
+	}
+}
+
+func TestExpressionErrorsInSubqueries(t *testing.T) {
+	cases := map[string]*Select{
+		"column": NewMaryBuilder().Select("id").
+			AddColumn(NewMaryBuilder().Select(NewCase()).From("users", ""), "sub").From("users", ""),
+		"source": NewMaryBuilder().Select("id").
+			From(NewMaryBuilder().Select("id").From("users", "").Where().And(Raw("a = ?")), "s"),
+		"join": NewMaryBuilder().Select("id").From("users", "u").
+			Left(NewMaryBuilder().Select("id").From("roles", "").Where().AndParam(NewCase(), "=", 1), "r", "r.id = u.id"),
+		"filter": NewMaryBuilder().Select("id").From("users", "").Where().
+			AndParam(NewMaryBuilder().Select("COUNT(*)").From("roles", "").Where().AndParam(NewCase(), "=", 1), ">", 0),
+	}
+
+	for name, b := range cases {
+		if !errors.Is(b.Err(), ErrInvalidExpression) {
+			t.Logf("%s: expected the subquery error %v, got %v", name, ErrInvalidExpression, b.Err())
+			t.FailNow()
+		}
+	}
+
+	outer := NewMaryBuilder().Select("id").AddColumn(NewMaryBuilder().Select(jsonPath{"data", ""}), "sub")
+
+	if outer.Err() == nil || outer.Err().Error() != "empty path" {
+		t.Logf("expected the error of the custom expression, got %v", outer.Err())
+		t.FailNow()
+	}
+}
@@ -0,0 +1,490 @@
+package obreron
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilterType es el tipo de dato de un campo filtrable, usado para convertir los valores recibidos
+type FilterType int8
+
+const (
+	// FilterString no convierte el valor recibido
+	FilterString = FilterType(iota)
+
+	// FilterInt convierte el valor recibido a int64
+	FilterInt
+
+	// FilterFloat convierte el valor recibido a float64
+	FilterFloat
+
+	// FilterBool convierte el valor recibido a bool
+	FilterBool
+
+	// FilterTime convierte el valor recibido a time.Time. Se aceptan los formatos
+	// RFC3339, `2006-01-02 15:04:05` y `2006-01-02`
+	FilterTime
+)
+
+// FilterOp es un operador de filtro
+type FilterOp string
+
+const (
+	// OpEq compara por igualdad `=`
+	OpEq = FilterOp("eq")
+	// OpNe compara por desigualdad `<>`
+	OpNe = FilterOp("ne")
+	// OpGt compara con `>`
+	OpGt = FilterOp("gt")
+	// OpGte compara con `>=`
+	OpGte = FilterOp("gte")
+	// OpLt compara con `<`
+	OpLt = FilterOp("lt")
+	// OpLte compara con `<=`
+	OpLte = FilterOp("lte")
+	// OpLike busca el valor como subcadena usando LIKE. Los comodines del valor se escapan
+	OpLike = FilterOp("like")
+	// OpIn compara con una lista de valores separados por coma usando IN
+	OpIn = FilterOp("in")
+	// OpNotIn compara con una lista de valores separados por coma usando NOT IN
+	OpNotIn = FilterOp("nin")
+	// OpNull filtra con IS NULL si el valor es verdadero o con IS NOT NULL si es falso
+	OpNull = FilterOp("null")
+)
+
+// filterOperators asocia los operadores de filtro con su operador sql
+var filterOperators = map[FilterOp]string{
+	OpEq:    "=",
+	OpNe:    "<>",
+	OpGt:    ">",
+	OpGte:   ">=",
+	OpLt:    "<",
+	OpLte:   "<=",
+	OpLike:  "LIKE",
+	OpIn:    "IN",
+	OpNotIn: "NOT IN",
+	OpNull:  "IS NULL",
+}
+
+// FilterReason indica el motivo de un FilterError
+type FilterReason string
+
+const (
+	// ReasonUnknownField indica que el campo no está en el esquema
+	ReasonUnknownField = FilterReason("unknown_field")
+	// ReasonUnknownOperator indica que el operador no existe
+	ReasonUnknownOperator = FilterReason("unknown_operator")
+	// ReasonOperatorNotAllowed indica que el operador no está permitido para el campo
+	ReasonOperatorNotAllowed = FilterReason("operator_not_allowed")
+	// ReasonInvalidValue indica que el valor no pudo convertirse al tipo del campo
+	ReasonInvalidValue = FilterReason("invalid_value")
+	// ReasonMalformed indica que el documento de filtros está mal formado
+	ReasonMalformed = FilterReason("malformed")
+)
+
+// FilterError describe un filtro rechazado
+type FilterError struct {
+	Field  string       `json:"field"`
+	Op     FilterOp     `json:"op,omitempty"`
+	Value  string       `json:"value,omitempty"`
+	Reason FilterReason `json:"reason"`
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("obreron: filtro %q inválido (%s)", e.Field, e.Reason)
+}
+
+// FilterErrors agrupa todos los filtros rechazados en un parseo
+type FilterErrors []*FilterError
+
+func (e FilterErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i := range e {
+		msgs[i] = e[i].Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// FilterField define un campo filtrable
+type FilterField struct {
+	// Name es el nombre público del campo, usado en los parámetros de la petición
+	Name string
+	// Expr es la expresión sql a filtrar
+	Expr string
+	// Type es el tipo al que se convierten los valores recibidos
+	Type FilterType
+	// Ops son los operadores permitidos. Si está vacío solo se permite OpEq
+	Ops []FilterOp
+}
+
+// allows indica si el operador op está permitido para el campo
+func (f *FilterField) allows(op FilterOp) bool {
+	if len(f.Ops) == 0 {
+		return op == OpEq
+	}
+
+	for _, o := range f.Ops {
+		if o == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FilterCondition es un filtro validado y con sus valores ya convertidos, listo para agregarse a un Select
+type FilterCondition struct {
+	Field  FilterField
+	Op     FilterOp
+	Values []interface{}
+}
+
+// FilterSchema contiene los campos por los que se permite filtrar
+type FilterSchema struct {
+	fields map[string]FilterField
+}
+
+// NewFilterSchema devuelve un esquema de filtros con los campos fields
+func NewFilterSchema(fields ...FilterField) *FilterSchema {
+	fs := &FilterSchema{
+		fields: make(map[string]FilterField, len(fields)),
+	}
+
+	for _, f := range fields {
+		fs.fields[f.Name] = f
+	}
+
+	return fs
+}
+
+// ParseValues parsea filtros desde parámetros http como `status=eq:1&created_at=gte:2024-01-01&name=like:foo`.
+// Cada valor tiene la forma `operador:valor`; si no tiene un operador conocido se usa OpEq con el valor completo.
+// Los parámetros que no están en el esquema se ignoran, para convivir con otros como `sort` o `page`.
+// Las condiciones se devuelven ordenadas por nombre de campo para que el sql generado sea estable.
+func (fs *FilterSchema) ParseValues(v url.Values) ([]FilterCondition, error) {
+	names := make([]string, 0, len(v))
+	for name := range v {
+		if _, ok := fs.fields[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	conds := make([]FilterCondition, 0, len(names))
+	var errs FilterErrors
+
+	for _, name := range names {
+		field := fs.fields[name]
+
+		for _, raw := range v[name] {
+			op, value := OpEq, raw
+
+			if i := strings.IndexByte(raw, ':'); i > -1 {
+				if _, ok := filterOperators[FilterOp(raw[:i])]; ok {
+					op, value = FilterOp(raw[:i]), raw[i+1:]
+				}
+			}
+
+			var values []string
+			if op == OpIn || op == OpNotIn {
+				values = strings.Split(value, ",")
+			} else {
+				values = []string{value}
+			}
+
+			c, err := field.condition(op, values)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			conds = append(conds, c)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return conds, nil
+}
+
+// jsonFilter es un elemento de un documento de filtros json
+type jsonFilter struct {
+	Field string          `json:"field"`
+	Op    FilterOp        `json:"op"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ParseJSON parsea filtros desde un documento json con la forma
+//
+//	[{"field": "status", "op": "eq", "value": 1}, {"field": "id", "op": "in", "value": [1, 2]}]
+//
+// A diferencia de ParseValues, los campos que no están en el esquema son rechazados.
+// Si se omite op se usa OpEq. Las condiciones mantienen el orden del documento.
+func (fs *FilterSchema) ParseJSON(data []byte) ([]FilterCondition, error) {
+	var doc []jsonFilter
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, FilterErrors{{Reason: ReasonMalformed, Value: err.Error()}}
+	}
+
+	conds := make([]FilterCondition, 0, len(doc))
+	var errs FilterErrors
+
+	for _, jf := range doc {
+		field, ok := fs.fields[jf.Field]
+		if !ok {
+			errs = append(errs, &FilterError{Field: jf.Field, Op: jf.Op, Reason: ReasonUnknownField})
+			continue
+		}
+
+		if jf.Op == "" {
+			jf.Op = OpEq
+		}
+
+		values, err := jsonFilterValues(jf)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		c, err := field.condition(jf.Op, values)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		conds = append(conds, c)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return conds, nil
+}
+
+// jsonFilterValues lleva el valor de un filtro json a su representación como texto,
+// aceptando un arreglo solo para los operadores de lista
+func jsonFilterValues(jf jsonFilter) ([]string, *FilterError) {
+	invalid := &FilterError{Field: jf.Field, Op: jf.Op, Value: string(jf.Value), Reason: ReasonInvalidValue}
+
+	var raws []json.RawMessage
+	if jf.Op == OpIn || jf.Op == OpNotIn {
+		if err := json.Unmarshal(jf.Value, &raws); err != nil {
+			return nil, invalid
+		}
+	} else {
+		raws = []json.RawMessage{jf.Value}
+	}
+
+	values := make([]string, len(raws))
+
+	for i, raw := range raws {
+		d := json.NewDecoder(bytes.NewReader(raw))
+		d.UseNumber()
+
+		var v interface{}
+		if err := d.Decode(&v); err != nil {
+			return nil, invalid
+		}
+
+		switch tv := v.(type) {
+		case string:
+			values[i] = tv
+		case json.Number:
+			values[i] = tv.String()
+		case bool:
+			values[i] = strconv.FormatBool(tv)
+		default:
+			return nil, invalid
+		}
+	}
+
+	return values, nil
+}
+
+// condition valida el operador op y convierte los valores values al tipo del campo
+func (f *FilterField) condition(op FilterOp, values []string) (FilterCondition, *FilterError) {
+	c := FilterCondition{Field: *f, Op: op}
+
+	if _, ok := filterOperators[op]; !ok {
+		return c, &FilterError{Field: f.Name, Op: op, Reason: ReasonUnknownOperator}
+	}
+
+	if !f.allows(op) || (op == OpLike && f.Type != FilterString) {
+		return c, &FilterError{Field: f.Name, Op: op, Reason: ReasonOperatorNotAllowed}
+	}
+
+	if len(values) == 0 {
+		return c, &FilterError{Field: f.Name, Op: op, Reason: ReasonInvalidValue}
+	}
+
+	c.Values = make([]interface{}, 0, len(values))
+
+	for _, raw := range values {
+		var v interface{}
+		var err error
+
+		if op == OpNull {
+			v, err = strconv.ParseBool(raw)
+		} else {
+			v, err = convertFilterValue(f.Type, raw)
+		}
+
+		if err != nil {
+			return c, &FilterError{Field: f.Name, Op: op, Value: raw, Reason: ReasonInvalidValue}
+		}
+
+		c.Values = append(c.Values, v)
+	}
+
+	return c, nil
+}
+
+// filterTimeLayouts son los formatos aceptados para campos FilterTime
+var filterTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"}
+
+// convertFilterValue convierte raw al tipo t
+func convertFilterValue(t FilterType, raw string) (interface{}, error) {
+	switch t {
+	case FilterInt:
+		return strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	case FilterFloat:
+		return strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	case FilterBool:
+		return strconv.ParseBool(strings.TrimSpace(raw))
+	case FilterTime:
+		var err error
+		for _, layout := range filterTimeLayouts {
+			var tm time.Time
+			if tm, err = time.Parse(layout, strings.TrimSpace(raw)); err == nil {
+				return tm, nil
+			}
+		}
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+// likeEscaper escapa los comodines de LIKE
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// invalid devuelve el motivo por el que la condición no puede agregarse a la consulta, como una condición
+// construida a mano sin valores, o un string vacío si es válida
+func (c FilterCondition) invalid() FilterReason {
+	if _, ok := filterOperators[c.Op]; !ok {
+		return ReasonUnknownOperator
+	}
+
+	if len(c.Values) == 0 {
+		return ReasonInvalidValue
+	}
+
+	switch c.Op {
+	case OpNull:
+		if _, ok := c.Values[0].(bool); !ok {
+			return ReasonInvalidValue
+		}
+	case OpLike:
+		if _, ok := c.Values[0].(string); !ok {
+			return ReasonInvalidValue
+		}
+	}
+
+	return ""
+}
+
+// Filter agrega las condiciones conds a la clausula WHERE usando conector AND y parámetros para todos los valores.
+// Si la clausula WHERE no se ha iniciado, se inicia. Las condiciones inválidas se descartan y Err devuelve un *FilterError
+func (s *Select) Filter(conds ...FilterCondition) *Select {
+	if len(conds) == 0 {
+		return s
+	}
+
+	s.touch()
+
+	if s.filter.Len() == 0 {
+		s.Where()
+	}
+
+	for _, c := range conds {
+		if reason := c.invalid(); reason != "" {
+			s.setErr(&FilterError{Field: c.Field.Name, Op: c.Op, Reason: reason})
+			continue
+		}
+
+		s.filter.WriteString(" AND ")
+		s.filter.WriteString(c.Field.Expr)
+
+		switch c.Op {
+		case OpNull:
+			if c.Values[0].(bool) {
+				s.filter.WriteString(" IS NULL")
+			} else {
+				s.filter.WriteString(" IS NOT NULL")
+			}
+		case OpIn, OpNotIn:
+			s.filter.WriteByte(' ')
+			s.filter.WriteString(filterOperators[c.Op])
+			s.filter.WriteByte(' ')
+			s.filter.WriteString(s.filter.Dialect().OpenEnclose())
+			for i := range c.Values {
+				if i > 0 {
+					s.filter.WriteByte(',')
+				}
+				s.filter.WriteString(s.filter.Dialect().ParamMark())
+			}
+			s.filter.WriteString(s.filter.Dialect().CloseEnclose())
+			s.filter.AddParam(c.Values...)
+		case OpLike:
+			s.filter.WriteString(" LIKE ")
+			s.filter.WriteString(s.filter.Dialect().ParamMark())
+			s.filter.AddParam("%" + likeEscaper.Replace(c.Values[0].(string)) + "%")
+			// el escape debe declararse, ya que no todos los motores usan `\` por defecto
+			if mysqlLexing(s.filter.Dialect()) {
+				s.filter.WriteString(` ESCAPE '\\'`)
+			} else {
+				s.filter.WriteString(` ESCAPE '\'`)
+			}
+		default:
+			s.filter.WriteByte(' ')
+			s.filter.WriteString(filterOperators[c.Op])
+			s.filter.WriteByte(' ')
+			s.filter.WriteString(s.filter.Dialect().ParamMark())
+			s.filter.AddParam(c.Values[0])
+		}
+	}
+
+	return s
+}
+
+// FilterValues parsea los filtros de v según el esquema fs y los agrega a la clausula WHERE.
+// Si algún filtro es rechazado devuelve FilterErrors y no agrega ninguna condición.
+func (s *Select) FilterValues(fs *FilterSchema, v url.Values) (*Select, error) {
+	conds, err := fs.ParseValues(v)
+	if err != nil {
+		return s, err
+	}
+
+	return s.Filter(conds...), nil
+}
+
+// FilterJSON parsea el documento de filtros data según el esquema fs y los agrega a la clausula WHERE.
+// Si algún filtro es rechazado devuelve FilterErrors y no agrega ninguna condición.
+func (s *Select) FilterJSON(fs *FilterSchema, data []byte) (*Select, error) {
+	conds, err := fs.ParseJSON(data)
+	if err != nil {
+		return s, err
+	}
+
+	return s.Filter(conds...), nil
+}
@@ -0,0 +1,205 @@
+package obreron
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+var userFilters = NewFilterSchema(
+	FilterField{Name: "status", Expr: "u.user_status", Type: FilterInt, Ops: []FilterOp{OpEq, OpIn}},
+	FilterField{Name: "created_at", Expr: "u.created_at", Type: FilterTime, Ops: []FilterOp{OpGte, OpLt}},
+	FilterField{Name: "name", Expr: "u.user_name", Type: FilterString, Ops: []FilterOp{OpEq, OpLike}},
+	FilterField{Name: "deleted_at", Expr: "u.deleted_at", Type: FilterTime, Ops: []FilterOp{OpNull}},
+	FilterField{Name: "score", Expr: "u.score", Type: FilterFloat},
+)
+
+func TestFilterValues(t *testing.T) {
+	v, err := url.ParseQuery("status=eq:1&created_at=gte:2024-01-01&name=like:50%25_off&deleted_at=null:true&page=2&score=4.5")
+
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	b := NewMaryBuilder().Select("user_id").From("users", "u")
+
+	_, err = b.FilterValues(userFilters, v)
+
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	q, p := b.Build()
+	expected := "SELECT user_id FROM users u  WHERE 1=1  AND u.created_at >= ? AND u.deleted_at IS NULL AND u.user_name LIKE ? ESCAPE '\\\\' AND u.score = ? AND u.user_status = ?"
+
+	if q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+
+	if len(p) != 4 {
+		t.Logf("expected 4 params, got %v", p)
+		t.FailNow()
+	}
+
+	if tm, ok := p[0].(time.Time); !ok || !tm.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Logf("expected time param, got %#v", p[0])
+		t.Fail()
+	}
+
+	if p[1] != `%50\%\_off%` {
+		t.Logf("expected escaped like pattern, got %#v", p[1])
+		t.Fail()
+	}
+
+	if p[2] != 4.5 || p[3] != int64(1) {
+		t.Logf("unexpected params %#v", p)
+		t.Fail()
+	}
+}
+
+func TestFilterValuesIn(t *testing.T) {
+	v := url.Values{"status": {"in:1,2,3"}}
+
+	b := NewMaryBuilder().Select("user_id").From("users", "u").Where().And("u.user_type = 2")
+
+	if _, err := b.FilterValues(userFilters, v); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	q, p := b.Build()
+	expected := "SELECT user_id FROM users u  WHERE 1=1  AND u.user_type = 2 AND u.user_status IN (?,?,?)"
+
+	if q != expected || len(p) != 3 || p[2] != int64(3) {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s %v", q, p)
+		t.FailNow()
+	}
+}
+
+func TestFilterValuesErrors(t *testing.T) {
+	v := url.Values{
+		"status":     {"gt:1"},
+		"created_at": {"gte:ayer"},
+		"name":       {"foo:bar"},
+		"score":      {"like:4"},
+	}
+
+	b := NewMaryBuilder().Select("user_id").From("users", "u")
+	_, err := b.FilterValues(userFilters, v)
+
+	var errs FilterErrors
+	if !errors.As(err, &errs) {
+		t.Logf("expected FilterErrors, got %v", err)
+		t.FailNow()
+	}
+
+	expected := map[string]FilterReason{
+		"status":     ReasonOperatorNotAllowed,
+		"created_at": ReasonInvalidValue,
+		"score":      ReasonOperatorNotAllowed,
+	}
+
+	if len(errs) != len(expected) {
+		t.Logf("expected %d errors, got %v", len(expected), errs)
+		t.FailNow()
+	}
+
+	for _, e := range errs {
+		if expected[e.Field] != e.Reason {
+			t.Logf("field %s: expected %s, got %s", e.Field, expected[e.Field], e.Reason)
+			t.Fail()
+		}
+	}
+
+	if q := b.String(); q != "SELECT user_id FROM users u " {
+		t.Logf("no condition should be added, generated: %s", q)
+		t.Fail()
+	}
+}
+
+func TestFilterJSON(t *testing.T) {
+	doc := []byte(`[
+		{"field": "status", "op": "in", "value": [1, 2]},
+		{"field": "name", "value": "Mary"},
+		{"field": "deleted_at", "op": "null", "value": false}
+	]`)
+
+	b := NewMaryBuilder().Select("user_id").From("users", "u")
+
+	if _, err := b.FilterJSON(userFilters, doc); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	q, p := b.Build()
+	expected := "SELECT user_id FROM users u  WHERE 1=1  AND u.user_status IN (?,?) AND u.user_name = ? AND u.deleted_at IS NOT NULL"
+
+	if q != expected || len(p) != 3 || p[0] != int64(1) || p[2] != "Mary" {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s %v", q, p)
+		t.FailNow()
+	}
+}
+
+func TestFilterJSONErrors(t *testing.T) {
+	cases := []struct {
+		doc    string
+		reason FilterReason
+	}{
+		{`{"status": 1}`, ReasonMalformed},
+		{`[{"field": "password", "value": "x"}]`, ReasonUnknownField},
+		{`[{"field": "status", "op": "between", "value": 1}]`, ReasonUnknownOperator},
+		{`[{"field": "status", "value": 1.5}]`, ReasonInvalidValue},
+		{`[{"field": "status", "value": [1]}]`, ReasonInvalidValue},
+		{`[{"field": "status", "op": "in", "value": []}]`, ReasonInvalidValue},
+	}
+
+	for _, c := range cases {
+		_, err := userFilters.ParseJSON([]byte(c.doc))
+
+		var errs FilterErrors
+		if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Reason != c.reason {
+			t.Logf("doc %s: expected %s, got %v", c.doc, c.reason, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestFilterLikeEscapeByDialect(t *testing.T) {
+	name := FilterField{Name: "name", Expr: "name", Type: FilterString, Ops: []FilterOp{OpLike}}
+	q := NewBuilder(Sqlite{}).Select("id").From("users", "").
+		Filter(FilterCondition{Field: name, Op: OpLike, Values: []interface{}{"50%"}}).String()
+
+	expected := `SELECT id FROM users WHERE 1=1  AND name LIKE ? ESCAPE '\'`
+
+	if q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+}
+
+func TestFilterInvalidCondition(t *testing.T) {
+	field := FilterField{Name: "status", Expr: "status"}
+
+	for _, c := range []FilterCondition{
+		{Field: field, Op: OpEq},
+		{Field: field, Op: OpNull, Values: []interface{}{"yes"}},
+		{Field: field, Op: OpLike, Values: []interface{}{1}},
+		{Field: field, Op: "between", Values: []interface{}{1}},
+	} {
+		b := NewMaryBuilder().Select("id").From("users", "").Filter(c)
+
+		var fe *FilterError
+		if !errors.As(b.Err(), &fe) || fe.Field != "status" {
+			t.Logf("expected a *FilterError for %+v, got %v", c, b.Err())
+			t.FailNow()
+		}
+	}
+}
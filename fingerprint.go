@@ -0,0 +1,205 @@
+package obreron
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// Fingerprint identifica la forma de una consulta, sin importar los valores de sus parámetros o literales.
+// Sirve para agrupar métricas o registros por consulta
+type Fingerprint struct {
+	// Hash es el hash FNV-1a de 64 bits de Text
+	Hash uint64
+	// Text es la consulta normalizada
+	Text string
+}
+
+// String devuelve Hash en hexadecimal, con 16 dígitos
+func (f Fingerprint) String() string {
+	h := strconv.FormatUint(f.Hash, 16)
+	return strings.Repeat("0", 16-len(h)) + h
+}
+
+// FingerprintSQL normaliza la consulta q del dialecto d y calcula su Fingerprint.
+//
+// Al normalizar, los literales de texto, números y marcas numeradas como `$1` se reemplazan por `?`, las
+// listas de IN y las filas de VALUES se colapsan en una sola, se descartan los comentarios, salvo las pistas
+// del optimizador `/*+ */`, y los espacios quedan como en FormatCanonical. Así, dos consultas con distinto sql tienen distinto
+// Fingerprint, pero no dos ejecuciones de la misma consulta con distintos valores
+func FingerprintSQL(d Dialect, q string) Fingerprint {
+	mysqlish := mysqlLexing(d)
+	text := formatTokens(normalizeTokens(tokenize(q, mysqlish), mysqlish), FormatCanonical)
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(text))
+
+	return Fingerprint{Hash: h.Sum64(), Text: text}
+}
+
+// FingerprintOf construye b y calcula el Fingerprint de su sql
+func FingerprintOf(b Builder) Fingerprint {
+	q, _ := b.Build()
+	return FingerprintSQL(b.Dialect(), q)
+}
+
+// Fingerprint calcula el Fingerprint de la consulta, ver FingerprintSQL
+func (s *Select) Fingerprint() Fingerprint {
+	return FingerprintSQL(s.Dialect(), s.String())
+}
+
+// Fingerprint calcula el Fingerprint de la sentencia, ver FingerprintSQL
+func (s *Insert) Fingerprint() Fingerprint {
+	return FingerprintSQL(s.Dialect(), s.String())
+}
+
+// Fingerprint calcula el Fingerprint de la sentencia, ver FingerprintSQL
+func (s *Update) Fingerprint() Fingerprint {
+	return FingerprintSQL(s.Dialect(), s.String())
+}
+
+// Fingerprint calcula el Fingerprint del sql del template, ver FingerprintSQL
+func (t *Template) Fingerprint() Fingerprint {
+	return FingerprintSQL(t.dialect, t.q)
+}
+
+// placeholderToken reemplaza a los literales al normalizar una consulta
+var placeholderToken = sqlToken{text: "?", kind: tokOther}
+
+// normalizeTokens reemplaza los literales de toks por marcas de parámetro, descarta los comentarios y
+// colapsa las listas de IN y las filas de VALUES. Si dquote es verdadero, los textos entre comillas
+// dobles se consideran literales, como en mysql
+func normalizeTokens(toks []sqlToken, dquote bool) []sqlToken {
+	out := make([]sqlToken, 0, len(toks))
+
+	for _, t := range toks {
+		switch {
+		case t.kind == tokComment && !strings.HasPrefix(t.text, "/*+"), t.kind == tokLineComment:
+			continue
+		case t.kind == tokQuoted && (t.text[0] == '\'' || (dquote && t.text[0] == '"')),
+			t.kind == tokWord && t.text[0] >= '0' && t.text[0] <= '9',
+			t.kind == tokWord && isNumberedMark(t.text):
+			p := placeholderToken
+			p.space = t.space
+			t = p
+		}
+
+		out = append(out, t)
+	}
+
+	return collapseLists(out)
+}
+
+// isNumberedMark indica si w es una marca de parámetro numerada como `$1`, de modo que el Fingerprint de una
+// consulta no cambie al convertir sus marcas con Rebind
+func isNumberedMark(w string) bool {
+	if len(w) < 2 || w[0] != '$' {
+		return false
+	}
+
+	for i := 1; i < len(w); i++ {
+		if w[i] < '0' || w[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// collapseLists colapsa las listas de marcas de parámetro de IN, como `IN (?, ?, ?)`, en `IN (...)`,
+// y las filas repetidas de VALUES en la primera
+func collapseLists(toks []sqlToken) []sqlToken {
+	out := make([]sqlToken, 0, len(toks))
+
+	for i := 0; i < len(toks); i++ {
+		t := toks[i]
+		out = append(out, t)
+
+		if t.kind != tokWord || i+1 >= len(toks) || toks[i+1].kind != tokOpen {
+			continue
+		}
+
+		switch strings.ToUpper(t.text) {
+		case "IN":
+			end := groupEnd(toks, i+1)
+			if end < 0 || !onlyPlaceholders(toks[i+2:end]) {
+				continue
+			}
+
+			out = append(out, toks[i+1], sqlToken{text: "...", kind: tokOther}, toks[end])
+			i = end
+		case "VALUES":
+			end := groupEnd(toks, i+1)
+			if end < 0 {
+				continue
+			}
+
+			row := toks[i+1 : end+1]
+			out = append(out, row...)
+			i = end
+
+			// descarta las filas siguientes que sean iguales a la primera
+			for i+1 < len(toks) && toks[i+1].kind == tokComma {
+				next := groupEnd(toks, i+2)
+				if next < 0 || !sameTokens(row, toks[i+2:next+1]) {
+					break
+				}
+				i = next
+			}
+		}
+	}
+
+	return out
+}
+
+// groupEnd devuelve la posición del paréntesis que cierra al que abre en toks[i], o -1 si no lo hay
+func groupEnd(toks []sqlToken, i int) int {
+	if i >= len(toks) || toks[i].kind != tokOpen {
+		return -1
+	}
+
+	depth := 0
+	for ; i < len(toks); i++ {
+		switch toks[i].kind {
+		case tokOpen:
+			depth++
+		case tokClose:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// onlyPlaceholders indica si toks es una lista no vacía de marcas de parámetro separadas por comas
+func onlyPlaceholders(toks []sqlToken) bool {
+	if len(toks) == 0 {
+		return false
+	}
+
+	for i, t := range toks {
+		if (i%2 == 0 && t.text != "?") || (i%2 == 1 && t.kind != tokComma) {
+			return false
+		}
+	}
+
+	return len(toks)%2 == 1
+}
+
+// sameTokens indica si a y b tienen los mismos tokens, sin considerar los espacios
+func sameTokens(a, b []sqlToken) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].text != b[i].text {
+			return false
+		}
+	}
+
+	return true
+}
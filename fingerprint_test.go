@@ -0,0 +1,94 @@
+package obreron
+
+import "testing"
+
+func TestFingerprintIgnoresValues(t *testing.T) {
+	build := func(status int, ids ...interface{}) *Select {
+		b := NewMaryBuilder()
+		b.Select("id").From("users", "u").Where().
+			AndParam("status", "=", status).
+			And("kind = 'admin'").
+			Filter(FilterCondition{Field: FilterField{Name: "id", Expr: "id"}, Op: OpIn, Values: ids}).
+			Limit(int64(len(ids)))
+		return b
+	}
+
+	a := build(1, 1, 2, 3).Fingerprint()
+	b := build(2, 4).Fingerprint()
+
+	expected := "SELECT id FROM users u WHERE ?=? AND status = ? AND kind = ? AND id IN (...) LIMIT ?"
+
+	if a != b || a.Text != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s %s", a.Text, b.Text)
+		t.FailNow()
+	}
+
+	if len(a.String()) != 16 {
+		t.Logf("expected 16 hex digits, got %s", a)
+		t.FailNow()
+	}
+}
+
+func TestFingerprintDistinguishesShapes(t *testing.T) {
+	build := func(withName bool) *Select {
+		return NewMaryBuilder().Select("id").From("users", "u").Where().
+			AndParam("status", "=", 1).
+			AndParamIf(withName, "name", "=", "ana")
+	}
+
+	if a, b := build(true).Fingerprint(), build(false).Fingerprint(); a.Hash == b.Hash {
+		t.Logf("expected different fingerprints for %s and %s", a.Text, b.Text)
+		t.FailNow()
+	}
+}
+
+func TestFingerprintSQL(t *testing.T) {
+	cases := []struct {
+		d        Dialect
+		a, b     string
+		expected string
+	}{
+		{
+			Mysql{}, "SELECT  *  FROM t /* a */ WHERE a = \"x\" -- nota\n", "select * from t where a = 'y'",
+			"",
+		},
+		{
+			Mysql{}, "INSERT INTO t (a,b) VALUES (?,?),(?,?)", "INSERT INTO t (a, b) VALUES (?, ?)",
+			"INSERT INTO t (a, b) VALUES (?, ?)",
+		},
+		{
+			Postgres{}, "SELECT /*+ IndexScan(t) */ \"a\" FROM t WHERE b IN (1, 2)", "SELECT /*+ IndexScan(t) */ \"a\" FROM t WHERE b IN (?)",
+			"SELECT /*+ IndexScan(t) */ \"a\" FROM t WHERE b IN (...)",
+		},
+	}
+
+	for _, c := range cases {
+		a, b := FingerprintSQL(c.d, c.a), FingerprintSQL(c.d, c.b)
+
+		if c.expected == "" {
+			// distinto uso de mayúsculas es distinto sql
+			if a == b {
+				t.Logf("expected different fingerprints: %s", a.Text)
+				t.FailNow()
+			}
+			continue
+		}
+
+		if a != b || a.Text != c.expected {
+			t.Logf("expected : %s", c.expected)
+			t.Logf("generated: %s | %s", a.Text, b.Text)
+			t.FailNow()
+		}
+	}
+}
+
+func TestFingerprintInsert(t *testing.T) {
+	a := NewMaryInsert("users").Columns("id", "name").Values(1, "ana").Values(2, "bea").Fingerprint()
+	b := NewMaryInsert("users").Columns("id", "name").Values(3, "eva").Fingerprint()
+
+	if a != b {
+		t.Logf("expected equal fingerprints: %s | %s", a.Text, b.Text)
+		t.FailNow()
+	}
+}
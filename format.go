@@ -0,0 +1,210 @@
+package obreron
+
+import "strings"
+
+// FormatStyle indica cómo dar formato al sql de una consulta, ver FormatSQL
+type FormatStyle int8
+
+const (
+	// FormatCanonical deja el sql en una línea, separando las palabras con un solo espacio
+	FormatCanonical = FormatStyle(0)
+
+	// FormatIndented deja cada clausula en su propia línea e indenta las subconsultas
+	FormatIndented = FormatStyle(1)
+)
+
+// formatIndent es la indentación de cada nivel de subconsulta en FormatIndented
+const formatIndent = "  "
+
+// tokenKind clasifica los tokens de una consulta al darle formato
+type tokenKind int8
+
+const (
+	tokWord = tokenKind(iota)
+	tokQuoted
+	tokComment
+	tokLineComment
+	tokOpen
+	tokClose
+	tokComma
+	tokOther
+)
+
+// sqlToken es un token de una consulta. space indica si en la consulta original le precedía un espacio
+type sqlToken struct {
+	text  string
+	kind  tokenKind
+	space bool
+}
+
+// clauseWords son las palabras que inician una clausula, y que FormatIndented deja al inicio de una línea
+var clauseWords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "GROUP": true, "HAVING": true, "WINDOW": true, "ORDER": true,
+	"LIMIT": true, "OFFSET": true, "UNION": true, "INTERSECT": true, "EXCEPT": true, "FOR": true, "LOCK": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "CROSS": true, "NATURAL": true,
+	"STRAIGHT_JOIN": true,
+}
+
+// joinWords son las palabras tras las que una clausula continúa en la misma línea, como en `LEFT OUTER JOIN`
+var joinWords = map[string]bool{
+	"INNER": true, "LEFT": true, "RIGHT": true, "FULL": true, "OUTER": true, "CROSS": true, "NATURAL": true,
+	"DISTINCT": true,
+}
+
+// FormatSQL da formato a la consulta q del dialecto d según style. Solo cambian los espacios y saltos de
+// línea fuera de los literales y comentarios, así que las marcas de parámetro y su orden se mantienen y
+// los parámetros de la consulta siguen siendo válidos
+func FormatSQL(d Dialect, q string, style FormatStyle) string {
+	return formatTokens(tokenize(q, mysqlLexing(d)), style)
+}
+
+// formatTokens escribe los tokens toks con el formato style
+func formatTokens(toks []sqlToken, style FormatStyle) string {
+	var sb strings.Builder
+	sb.Grow(len(toks) * 6)
+
+	// subs indica, para cada paréntesis abierto, si encierra una subconsulta
+	var subs []bool
+	level := 0
+	lineStart := true
+
+	newLine := func() {
+		sb.WriteByte('\n')
+		for i := 0; i < level; i++ {
+			sb.WriteString(formatIndent)
+		}
+		lineStart = true
+	}
+
+	for i, t := range toks {
+		var prev sqlToken
+		if i > 0 {
+			prev = toks[i-1]
+		}
+
+		inQuery := len(subs) == 0 || subs[len(subs)-1]
+
+		switch {
+		case i > 0 && prev.kind == tokLineComment:
+			// un comentario de línea se tragaría lo que venga después en la misma línea
+			newLine()
+		case t.kind == tokClose && len(subs) > 0 && subs[len(subs)-1] && style == FormatIndented:
+			level--
+			newLine()
+		case t.kind == tokWord && style == FormatIndented && inQuery && !lineStart &&
+			clauseWords[strings.ToUpper(t.text)] && !joinWords[strings.ToUpper(prev.text)] && !isCall(toks, i):
+			newLine()
+		case lineStart, prev.kind == tokOpen, t.kind == tokClose, t.kind == tokComma:
+		case prev.kind == tokComma, t.space:
+			sb.WriteByte(' ')
+		}
+
+		sb.WriteString(t.text)
+		lineStart = false
+
+		switch t.kind {
+		case tokOpen:
+			sub := isSubquery(toks, i)
+			subs = append(subs, sub)
+
+			if sub && style == FormatIndented {
+				level++
+				newLine()
+			}
+		case tokClose:
+			if len(subs) > 0 {
+				subs = subs[:len(subs)-1]
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// Formatted devuelve el sql de la consulta con el formato style. Los parámetros no cambian, ver FormatSQL
+func (s *Select) Formatted(style FormatStyle) string {
+	return FormatSQL(s.Dialect(), s.String(), style)
+}
+
+// isSubquery indica si el paréntesis toks[i] abre una subconsulta
+func isSubquery(toks []sqlToken, i int) bool {
+	for i++; i < len(toks); i++ {
+		switch toks[i].kind {
+		case tokComment, tokLineComment:
+			continue
+		case tokWord:
+			w := strings.ToUpper(toks[i].text)
+			return w == "SELECT" || w == "WITH"
+		}
+		return false
+	}
+	return false
+}
+
+// isCall indica si la palabra toks[i] es el nombre de una función, como en `LEFT(nombre, 3)`
+func isCall(toks []sqlToken, i int) bool {
+	return i+1 < len(toks) && toks[i+1].kind == tokOpen && !toks[i+1].space
+}
+
+// tokenize divide q en tokens, respetando los literales, identificadores escapados y comentarios. mysql indica
+// si q sigue las reglas léxicas de mysql, ver mysqlLexing
+func tokenize(q string, mysql bool) []sqlToken {
+	toks := make([]sqlToken, 0, len(q)/4)
+	space := false
+
+	for i := 0; i < len(q); {
+		c := q[i]
+		start := i
+		kind := tokOther
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			space = true
+			i++
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			kind = tokQuoted
+			i = skipQuoted(q, i, mysql)
+		case (c == '-' || c == '#' || c == '/') && skipComment(q, i, mysql) > i:
+			kind = tokComment
+			if c != '/' {
+				kind = tokLineComment
+			}
+			i = skipComment(q, i, mysql)
+		case c == '(':
+			kind = tokOpen
+			i++
+		case c == ')':
+			kind = tokClose
+			i++
+		case c == ',':
+			kind = tokComma
+			i++
+		case isWordByte(c):
+			kind = tokWord
+			for i < len(q) && isWordByte(q[i]) {
+				i++
+			}
+		case strings.IndexByte(operatorBytes, c) >= 0:
+			for i < len(q) && strings.IndexByte(operatorBytes, q[i]) >= 0 && skipComment(q, i, mysql) == i {
+				i++
+			}
+		default:
+			i++
+		}
+
+		toks = append(toks, sqlToken{text: q[start:i], kind: kind, space: space})
+		space = false
+	}
+
+	return toks
+}
+
+// operatorBytes son los caracteres que se agrupan en un solo token, como `<=` o `::`
+const operatorBytes = "<>=!|:+-*/%&^~#"
+
+// isWordByte indica si c puede formar parte de una palabra, identificador o número
+func isWordByte(c byte) bool {
+	return c == '_' || c == '.' || c == '$' || c == '@' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,84 @@
+package obreron
+
+import (
+	"reflect"
+	"testing"
+)
+
+func formatTestBuilder() *Select {
+	sub := NewMaryBuilder().Select("user_id").From("orders", "o").Where().AndParam("total", ">", 100)
+
+	b := NewMaryBuilder()
+	b.Select("u.id", "u.name").AddColumn("count(*)", "total").From("users", "u").
+		Left("roles", "r", "r.user_id = u.id").
+		Inner(sub, "s", "s.user_id = u.id").
+		Where().AndParam("u.name", "=", "a  b").
+		GroupBy("u.id, u.name").OrderBy("u.name ASC").Limit(10)
+
+	return b
+}
+
+func TestFormatCanonical(t *testing.T) {
+	b := formatTestBuilder()
+	params := b.Params()
+
+	expected := "SELECT u.id, u.name, count(*) AS total FROM users u LEFT JOIN roles r ON r.user_id = u.id INNER JOIN (SELECT user_id FROM orders o WHERE 1=1 AND total > ?) s ON s.user_id = u.id WHERE 1=1 AND u.name = ? GROUP BY u.id, u.name ORDER BY u.name ASC LIMIT 10"
+
+	if q := b.Formatted(FormatCanonical); q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+
+	if !reflect.DeepEqual(b.Params(), params) {
+		t.Logf("params changed: %v %v", params, b.Params())
+		t.FailNow()
+	}
+}
+
+func TestFormatIndented(t *testing.T) {
+	b := formatTestBuilder()
+
+	expected := `SELECT u.id, u.name, count(*) AS total
+FROM users u
+LEFT JOIN roles r ON r.user_id = u.id
+INNER JOIN (
+  SELECT user_id
+  FROM orders o
+  WHERE 1=1 AND total > ?
+) s ON s.user_id = u.id
+WHERE 1=1 AND u.name = ?
+GROUP BY u.id, u.name
+ORDER BY u.name ASC
+LIMIT 10`
+
+	if q := b.Formatted(FormatIndented); q != expected {
+		t.Logf("expected :\n%s", expected)
+		t.Logf("generated:\n%s", q)
+		t.FailNow()
+	}
+}
+
+func TestFormatKeepsLiteralsAndComments(t *testing.T) {
+	cases := []struct {
+		d        Dialect
+		q        string
+		style    FormatStyle
+		expected string
+	}{
+		{Mysql{}, "SELECT  'a  ?\\'  b' ,  \"c  d\"  FROM t", FormatCanonical, "SELECT 'a  ?\\'  b', \"c  d\" FROM t"},
+		{Postgres{}, "SELECT 'a\\'  FROM  t", FormatCanonical, "SELECT 'a\\' FROM t"},
+		{Mysql{}, "SELECT a -- comentario\n ,b FROM t", FormatCanonical, "SELECT a -- comentario\n, b FROM t"},
+		{Mysql{}, "SELECT LEFT(name, 2) FROM t  LEFT JOIN u ON t.id=u.id", FormatIndented, "SELECT LEFT(name, 2)\nFROM t\nLEFT JOIN u ON t.id=u.id"},
+		{Postgres{}, "SELECT a FROM t WHERE a IS DISTINCT FROM b", FormatIndented, "SELECT a\nFROM t\nWHERE a IS DISTINCT FROM b"},
+		{Postgres{}, "SELECT extract(year FROM a) FROM t", FormatIndented, "SELECT extract(year FROM a)\nFROM t"},
+	}
+
+	for _, c := range cases {
+		if q := FormatSQL(c.d, c.q, c.style); q != c.expected {
+			t.Logf("expected : %q", c.expected)
+			t.Logf("generated: %q", q)
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package obreron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsupported indica que el dialecto de la consulta no soporta la cláusula pedida
+var ErrUnsupported = errors.New("obreron: no soportado por el dialecto")
+
+// ErrInvalidHint indica que una pista no puede aplicarse a la consulta
+var ErrInvalidHint = errors.New("obreron: pista inválida")
+
+// HintSupport es implementada por los dialectos que soportan las pistas de mysql: pistas de índice,
+// pistas del optimizador `/*+ */` y modificadores como SQL_NO_CACHE
+type HintSupport interface {
+	SupportsHints() bool
+}
+
+// DistinctOnSupport es implementada por los dialectos que soportan `DISTINCT ON (...)`, como postgresql
+type DistinctOnSupport interface {
+	SupportsDistinctOn() bool
+}
+
+// IndexHintKind es el tipo de una pista de índice
+type IndexHintKind string
+
+const (
+	// UseIndexHint sugiere usar solo los índices indicados
+	UseIndexHint = IndexHintKind("USE")
+
+	// ForceIndexHint obliga a usar los índices indicados
+	ForceIndexHint = IndexHintKind("FORCE")
+
+	// IgnoreIndexHint impide usar los índices indicados
+	IgnoreIndexHint = IndexHintKind("IGNORE")
+)
+
+// IndexHint es una pista de índice de mysql, como `USE INDEX FOR ORDER BY (idx)`
+type IndexHint struct {
+	Kind IndexHintKind
+	// For limita la pista a "JOIN", "ORDER BY" o "GROUP BY". Vacío la aplica a todo
+	For string
+	// Indexes son los nombres de los índices, que se escapan según el dialecto
+	Indexes []string
+}
+
+// selectModifier son los modificadores que se escriben entre SELECT y las columnas
+type selectModifier uint16
+
+const (
+	modStraightJoin = selectModifier(1 << iota)
+	modNoCache
+	modCalcFoundRows
+	modDistinct
+	modHighPriority
+	modBigResult
+)
+
+// modifierWords son las palabras de cada modificador, en el orden en que mysql las espera
+var modifierWords = []struct {
+	mod  selectModifier
+	word string
+}{
+	{modDistinct, "DISTINCT"},
+	{modHighPriority, "HIGH_PRIORITY"},
+	{modStraightJoin, "STRAIGHT_JOIN"},
+	{modBigResult, "SQL_BIG_RESULT"},
+	{modNoCache, "SQL_NO_CACHE"},
+	{modCalcFoundRows, "SQL_CALC_FOUND_ROWS"},
+}
+
+// Posiciones de la última tabla agregada a la consulta, a la que se aplican las pistas de índice
+const (
+	hintNone = int8(iota)
+	hintFrom
+	hintJoin
+)
+
+// IndexHint agrega las pistas de índice hints a la última tabla agregada con From o con un join.
+// En los joins las pistas se escriben antes de la clausula ON
+//
+//	b.From("users", "u").UseIndex("idx_status").Inner("roles", "r", "r.id = u.role_id").ForceIndex("PRIMARY")
+func (s *Select) IndexHint(hints ...IndexHint) *Select {
+	s.touch()
+
+	if !s.supportsHints() {
+		s.setErr(fmt.Errorf("%w: pistas de índice", ErrUnsupported))
+		return s
+	}
+
+	if s.hintTarget == hintNone {
+		s.setErr(fmt.Errorf("%w: no hay una tabla a la que aplicar la pista de índice", ErrInvalidHint))
+		return s
+	}
+
+	var sb strings.Builder
+
+	for _, h := range hints {
+		switch h.Kind {
+		case UseIndexHint, ForceIndexHint, IgnoreIndexHint:
+		default:
+			s.setErr(fmt.Errorf("%w: tipo de pista de índice %q", ErrInvalidHint, h.Kind))
+			return s
+		}
+
+		switch strings.ToUpper(h.For) {
+		case "", "JOIN", "ORDER BY", "GROUP BY":
+		default:
+			s.setErr(fmt.Errorf("%w: FOR %q", ErrInvalidHint, h.For))
+			return s
+		}
+
+		sb.WriteString(string(h.Kind))
+		sb.WriteString(" INDEX ")
+
+		if h.For != "" {
+			sb.WriteString("FOR ")
+			sb.WriteString(strings.ToUpper(h.For))
+			sb.WriteByte(' ')
+		}
+
+		sb.WriteString(s.OpenEnclose())
+		for i, idx := range h.Indexes {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(s.Quote(idx))
+		}
+		sb.WriteString(s.CloseEnclose())
+		sb.WriteByte(' ')
+	}
+
+	if s.hintTarget == hintFrom {
+		s.source.WriteString(sb.String())
+		return s
+	}
+
+	s.joins.insertAt(s.joinHintAt, sb.String())
+	s.joinHintAt += sb.Len()
+
+	return s
+}
+
+// UseIndex agrega `USE INDEX (indexes)` a la última tabla agregada, ver IndexHint
+func (s *Select) UseIndex(indexes ...string) *Select {
+	return s.IndexHint(IndexHint{Kind: UseIndexHint, Indexes: indexes})
+}
+
+// ForceIndex agrega `FORCE INDEX (indexes)` a la última tabla agregada, ver IndexHint
+func (s *Select) ForceIndex(indexes ...string) *Select {
+	return s.IndexHint(IndexHint{Kind: ForceIndexHint, Indexes: indexes})
+}
+
+// IgnoreIndex agrega `IGNORE INDEX (indexes)` a la última tabla agregada, ver IndexHint
+func (s *Select) IgnoreIndex(indexes ...string) *Select {
+	return s.IndexHint(IndexHint{Kind: IgnoreIndexHint, Indexes: indexes})
+}
+
+// OptimizerHint agrega la pista del optimizador hint, que se escribe en un comentario `/*+ */` justo después de SELECT
+func (s *Select) OptimizerHint(hint string) *Select {
+	s.touch()
+
+	if !s.supportsHints() {
+		s.setErr(fmt.Errorf("%w: pistas del optimizador", ErrUnsupported))
+		return s
+	}
+
+	s.optimizerHints = append(s.optimizerHints, hint)
+	return s
+}
+
+// MaxExecutionTime agrega la pista del optimizador `MAX_EXECUTION_TIME`, que limita la duración de la consulta a d
+func (s *Select) MaxExecutionTime(d time.Duration) *Select {
+	return s.OptimizerHint("MAX_EXECUTION_TIME(" + strconv.FormatInt(d.Milliseconds(), 10) + ")")
+}
+
+// Distinct agrega el modificador DISTINCT, que descarta las filas repetidas
+func (s *Select) Distinct() *Select {
+	s.touch()
+	s.modifiers |= modDistinct
+	return s
+}
+
+// DistinctOn agrega `DISTINCT ON (cols)` de postgresql, que deja solo la primera fila de cada grupo de filas
+// con los mismos valores en cols. Reemplaza a Distinct
+//
+//	b.Select("user_id", "created_at").DistinctOn("user_id").From("logins", "").OrderBy("user_id, created_at DESC")
+func (s *Select) DistinctOn(cols ...string) *Select {
+	s.touch()
+
+	if d, ok := s.Dialect().(DistinctOnSupport); !ok || !d.SupportsDistinctOn() {
+		s.setErr(fmt.Errorf("%w: DISTINCT ON", ErrUnsupported))
+		return s
+	}
+
+	s.distinctOn = append(s.distinctOn, cols...)
+	return s
+}
+
+// HighPriority agrega el modificador HIGH_PRIORITY de mysql
+func (s *Select) HighPriority() *Select {
+	return s.modifier(modHighPriority, "HIGH_PRIORITY")
+}
+
+// SQLBigResult agrega el modificador SQL_BIG_RESULT de mysql
+func (s *Select) SQLBigResult() *Select {
+	return s.modifier(modBigResult, "SQL_BIG_RESULT")
+}
+
+// StraightJoin agrega el modificador STRAIGHT_JOIN, que obliga a unir las tablas en el orden de la consulta
+func (s *Select) StraightJoin() *Select {
+	return s.modifier(modStraightJoin, "STRAIGHT_JOIN")
+}
+
+// SQLNoCache agrega el modificador SQL_NO_CACHE
+func (s *Select) SQLNoCache() *Select {
+	return s.modifier(modNoCache, "SQL_NO_CACHE")
+}
+
+// SQLCalcFoundRows agrega el modificador SQL_CALC_FOUND_ROWS
+func (s *Select) SQLCalcFoundRows() *Select {
+	return s.modifier(modCalcFoundRows, "SQL_CALC_FOUND_ROWS")
+}
+
+// Straight Agrega un STRAIGHT_JOIN, un inner join en que la tabla de la izquierda se lee primero
+func (s *Select) Straight(c interface{}, a string, on interface{}) *Select {
+	if !s.supportsHints() {
+		s.touch()
+		s.setErr(fmt.Errorf("%w: STRAIGHT_JOIN", ErrUnsupported))
+		return s
+	}
+
+	return s.join(" STRAIGHT_JOIN ", c, a, on)
+}
+
+// modifier activa el modificador mod, llamado word, si el dialecto lo soporta
+func (s *Select) modifier(mod selectModifier, word string) *Select {
+	s.touch()
+
+	if !s.supportsHints() {
+		s.setErr(fmt.Errorf("%w: %s", ErrUnsupported, word))
+		return s
+	}
+
+	s.modifiers |= mod
+	return s
+}
+
+// supportsHints indica si el dialecto de la consulta soporta las pistas de mysql
+func (s *Select) supportsHints() bool {
+	h, ok := s.Dialect().(HintSupport)
+	return ok && h.SupportsHints()
+}
+
+// writeModifiers escribe las pistas del optimizador, DISTINCT y los modificadores entre SELECT y las columnas,
+// en ese orden sin importar el orden en que se agregaron
+func (s *Select) writeModifiers() {
+	if len(s.optimizerHints) > 0 {
+		s.WriteString("/*+ ")
+		for _, h := range s.optimizerHints {
+			s.WriteString(escapeComment(h))
+			s.WriteByte(' ')
+		}
+		s.WriteString("*/ ")
+	}
+
+	if len(s.distinctOn) > 0 {
+		s.WriteString("DISTINCT ON ")
+		s.WriteString(s.OpenEnclose())
+		for i, c := range s.distinctOn {
+			if i > 0 {
+				s.WriteString(", ")
+			}
+			s.WriteString(c)
+		}
+		s.WriteString(s.CloseEnclose())
+		s.WriteByte(' ')
+	}
+
+	if s.modifiers == 0 {
+		return
+	}
+
+	for _, m := range modifierWords {
+		if m.mod == modDistinct && len(s.distinctOn) > 0 {
+			continue
+		}
+
+		if s.modifiers&m.mod != 0 {
+			s.WriteString(m.word)
+			s.WriteByte(' ')
+		}
+	}
+}
+
+// insertAt inserta text en la posición off del buffer
+func (sb *SQLBuilder) insertAt(off int, text string) {
+	tail := string(sb.Bytes()[off:])
+	sb.Truncate(off)
+	sb.WriteString(text)
+	sb.WriteString(tail)
+}
@@ -0,0 +1,119 @@
+package obreron
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIndexHints(t *testing.T) {
+	b := NewMaryBuilder().Select("u.id").
+		From("users", "u").UseIndex("idx_status").
+		Inner("roles", "r", "r.id = u.role_id").ForceIndex("PRIMARY").
+		IndexHint(IndexHint{Kind: IgnoreIndexHint, For: "order by", Indexes: []string{"idx_a", "idx_b"}}).
+		Left("groups", "g", "g.id = u.group_id").
+		Where().AndParam("u.status", "=", 1)
+
+	expected := "SELECT u.id FROM users u USE INDEX (`idx_status`)  INNER JOIN roles r FORCE INDEX (`PRIMARY`) IGNORE INDEX FOR ORDER BY (`idx_a`,`idx_b`)  ON r.id = u.role_id LEFT JOIN groups g  ON g.id = u.group_id WHERE 1=1  AND u.status = ?"
+
+	if q := b.String(); q != expected || b.Err() != nil {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s %v", q, b.Err())
+		t.FailNow()
+	}
+}
+
+func TestSelectModifiersAndOptimizerHints(t *testing.T) {
+	b := NewMaryBuilder().SQLCalcFoundRows().Select("id").From("users", "").
+		MaxExecutionTime(time.Second).OptimizerHint("NO_INDEX_MERGE(users) */").
+		SQLNoCache().StraightJoin().
+		Straight("roles", "r", "r.user_id = users.id")
+
+	expected := "SELECT /*+ MAX_EXECUTION_TIME(1000) NO_INDEX_MERGE(users) * / */ STRAIGHT_JOIN SQL_NO_CACHE SQL_CALC_FOUND_ROWS id FROM users STRAIGHT_JOIN roles r  ON r.user_id = users.id"
+
+	if q := b.String(); q != expected || b.Err() != nil {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s %v", q, b.Err())
+		t.FailNow()
+	}
+
+	b.Reset()
+
+	if q := b.Select("id").From("users", "").String(); q != "SELECT id FROM users" {
+		t.Logf("unexpected query after Reset: %s", q)
+		t.FailNow()
+	}
+}
+
+func TestOptimizerHintCannotBeClosed(t *testing.T) {
+	cases := map[string]string{
+		"/*/ x":                   "SELECT /*+ / * / x */ id FROM users",
+		"*/*/ ; DROP TABLE users": "SELECT /*+ * / * / ; DROP TABLE users */ id FROM users",
+		"**//":                    "SELECT /*+ ** // */ id FROM users",
+	}
+
+	for hint, expected := range cases {
+		q := NewMaryBuilder().Select("id").From("users", "").OptimizerHint(hint).String()
+
+		if q != expected || strings.Count(q, "*/") != 1 {
+			t.Logf("expected : %s", expected)
+			t.Logf("generated: %s", q)
+			t.FailNow()
+		}
+	}
+}
+
+func TestHintErrors(t *testing.T) {
+	cases := map[string]*Select{
+		"postgres index":  NewBuilder(Postgres{}).Select("id").From("users", "").UseIndex("idx"),
+		"sqlite no cache": NewBuilder(Sqlite{}).SQLNoCache().Select("id").From("users", ""),
+		"postgres hint":   NewBuilder(Postgres{}).Select("id").From("users", "").MaxExecutionTime(time.Second),
+		"no table":        NewMaryBuilder().Select("1").UseIndex("idx"),
+		"bad kind":        NewMaryBuilder().Select("id").From("users", "").IndexHint(IndexHint{Kind: "PREFER"}),
+		"mysql on":        NewMaryBuilder().Select("id").DistinctOn("id").From("users", ""),
+		"sqlite priority": NewBuilder(Sqlite{}).Select("id").HighPriority().From("users", ""),
+	}
+
+	for name, b := range cases {
+		want := ErrUnsupported
+		if name == "no table" || name == "bad kind" {
+			want = ErrInvalidHint
+		}
+
+		if !errors.Is(b.Err(), want) {
+			t.Logf("%s: expected %v, got %v", name, want, b.Err())
+			t.FailNow()
+		}
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	cases := []struct {
+		b        *Select
+		expected string
+	}{
+		{
+			NewMaryBuilder().Select("name").From("users", "").SQLBigResult().Distinct().
+				AddColumn("id", "").HighPriority().StraightJoin(),
+			"SELECT DISTINCT HIGH_PRIORITY STRAIGHT_JOIN SQL_BIG_RESULT name,id FROM users",
+		},
+		{
+			NewBuilder(Sqlite{}).Distinct().Select("name").From("users", ""),
+			"SELECT DISTINCT name FROM users",
+		},
+		{
+			NewBuilder(Postgres{}).Select("user_id", "created_at").From("logins", "").
+				OrderBy("user_id, created_at DESC").Distinct().DistinctOn("user_id", "device"),
+			"SELECT DISTINCT ON (user_id, device) user_id,created_at FROM logins ORDER BY user_id, created_at DESC ",
+		},
+	}
+
+	for _, c := range cases {
+		if q := c.b.String(); q != c.expected || c.b.Err() != nil {
+			t.Logf("expected : %s", c.expected)
+			t.Logf("generated: %s %v", q, c.b.Err())
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package obreron
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// ErrNotExecuted indica que un hook AroundExecute terminó sin ejecutar la sentencia ni devolver un error
+var ErrNotExecuted = errors.New("obreron: la sentencia no fue ejecutada")
+
+// ExecMode indica cómo se ejecuta una sentencia
+type ExecMode int8
+
+const (
+	// ModeBuild indica que la sentencia solo se construye, ver BuildContext
+	ModeBuild = ExecMode(0)
+
+	// ModeQuery indica que la sentencia se ejecuta con Query, ScanAll o ScanOne
+	ModeQuery = ExecMode(1)
+
+	// ModeQueryRow indica que la sentencia se ejecuta con QueryRow
+	ModeQueryRow = ExecMode(2)
+
+	// ModeExec indica que la sentencia se ejecuta con Exec
+	ModeExec = ExecMode(3)
+)
+
+// Statement es una sentencia construida, en su paso por los hooks
+type Statement struct {
+	Mode ExecMode
+
+	// SQL y Params son el sql y los parámetros a ejecutar. Los hooks AfterBuild pueden modificarlos
+	SQL    string
+	Params []interface{}
+
+	// Dialect es el dialecto de SQL. Es el del builder en los hooks AfterBuild, y el del Runner en los
+	// hooks AroundExecute, cuando las marcas de parámetro ya se convirtieron a las suyas
+	Dialect Dialect
+
+	// Builder es el builder que construyó la sentencia
+	Builder Builder
+
+	// Rows, Row y Result son el resultado de la ejecución según Mode. Solo están disponibles en los hooks
+	// AroundExecute después de llamar a next, y si la ejecución no falló
+	Rows   *sql.Rows
+	Row    *sql.Row
+	Result sql.Result
+
+	// RowCount es la cantidad de filas afectadas por Exec o leídas por ScanAll y ScanOne, o -1 si no se conoce,
+	// como en QueryRow, cuya fila se lee después de ejecutar los hooks
+	RowCount int64
+
+	executed bool
+}
+
+// Hook agrupa funciones que se ejecutan en las distintas etapas de una sentencia. Cualquiera puede ser nil.
+// Si una etapa devuelve un error la sentencia no se ejecuta y se devuelve ese error
+type Hook struct {
+	// BeforeBuild se llama antes de construir el builder b, y puede modificarlo
+	BeforeBuild func(ctx context.Context, b Builder) error
+
+	// AfterBuild se llama después de construir la sentencia, y puede reescribir su sql y parámetros.
+	// El sql aún tiene las marcas de parámetro `?`
+	AfterBuild func(ctx context.Context, st *Statement) error
+
+	// AroundExecute envuelve la ejecución de la sentencia, que ocurre al llamar a next. Para ScanAll y
+	// ScanOne la ejecución incluye la lectura de las filas
+	AroundExecute func(ctx context.Context, st *Statement, next func(ctx context.Context) error) error
+}
+
+var globalHooks struct {
+	sync.RWMutex
+	hooks []Hook
+}
+
+// AddHook registra h para todas las sentencias ejecutadas por obreron. Los hooks globales se ejecutan
+// antes que los del builder, en el orden en que se registraron
+func AddHook(h Hook) {
+	globalHooks.Lock()
+	defer globalHooks.Unlock()
+
+	// se copia el slice, ya que puede estar en uso por sentencias en curso
+	hooks := make([]Hook, len(globalHooks.hooks), len(globalHooks.hooks)+1)
+	copy(hooks, globalHooks.hooks)
+	globalHooks.hooks = append(hooks, h)
+}
+
+// ResetHooks elimina los hooks globales
+func ResetHooks() {
+	globalHooks.Lock()
+	defer globalHooks.Unlock()
+
+	globalHooks.hooks = nil
+}
+
+// Hook registra hooks que solo se aplican a este builder, después de los globales
+func (s *Select) Hook(hooks ...Hook) *Select {
+	s.hooks = append(s.hooks, hooks...)
+	return s
+}
+
+// Hook registra hooks que solo se aplican a este builder, después de los globales
+func (s *Insert) Hook(hooks ...Hook) *Insert {
+	s.hooks = append(s.hooks, hooks...)
+	return s
+}
+
+// Hook registra hooks que solo se aplican a este builder, después de los globales
+func (s *Update) Hook(hooks ...Hook) *Update {
+	s.hooks = append(s.hooks, hooks...)
+	return s
+}
+
+func (s *Select) hookList() []Hook { return s.hooks }
+func (s *Insert) hookList() []Hook { return s.hooks }
+func (s *Update) hookList() []Hook { return s.hooks }
+
+// hooksFor devuelve los hooks globales seguidos por los del builder b
+func hooksFor(b Builder) []Hook {
+	globalHooks.RLock()
+	global := globalHooks.hooks
+	globalHooks.RUnlock()
+
+	hb, ok := b.(interface{ hookList() []Hook })
+	if !ok || len(hb.hookList()) == 0 {
+		return global
+	}
+
+	if len(global) == 0 {
+		return hb.hookList()
+	}
+
+	hooks := make([]Hook, 0, len(global)+len(hb.hookList()))
+	hooks = append(hooks, global...)
+	return append(hooks, hb.hookList()...)
+}
+
+// BuildContext construye b pasando por los hooks BeforeBuild y AfterBuild, devolviendo el sql y los
+// parámetros resultantes. Úselo cuando ejecute la sentencia por su cuenta
+func BuildContext(ctx context.Context, b Builder) (string, []interface{}, error) {
+	st, _, err := prepare(ctx, nil, b, ModeBuild)
+	return st.SQL, st.Params, err
+}
+
+// prepare construye b pasando por los hooks BeforeBuild y AfterBuild, y si r no es nil convierte las
+// marcas de parámetro al dialecto de r. Devuelve además los hooks que aplican a la sentencia
+func prepare(ctx context.Context, r Runner, b Builder, mode ExecMode) (*Statement, []Hook, error) {
+	hooks := hooksFor(b)
+	st := &Statement{Mode: mode, Builder: b, Dialect: b.Dialect(), RowCount: -1}
+
+	for _, h := range hooks {
+		if h.BeforeBuild != nil {
+			if err := h.BeforeBuild(ctx, b); err != nil {
+				return st, hooks, err
+			}
+		}
+	}
+
+	st.SQL, st.Params = b.Build()
+
+	if eb, ok := b.(interface{ Err() error }); ok {
+		if err := eb.Err(); err != nil {
+			return st, hooks, wrapQueryError(err, st.SQL, st.Params)
+		}
+	}
+
+	st.SQL = addContextTags(ctx, b, st.SQL)
+
+	for _, h := range hooks {
+		if h.AfterBuild != nil {
+			if err := h.AfterBuild(ctx, st); err != nil {
+				return st, hooks, err
+			}
+		}
+	}
+
+	if r != nil {
+		st.Dialect = runnerDialect(r, st.Dialect)
+
+		if rb, ok := b.(prebound); ok {
+			st.SQL = rb.rebindTo(st.Dialect, st.SQL)
+		} else {
+			st.SQL = Rebind(st.Dialect, st.SQL)
+		}
+	}
+
+	return st, hooks, nil
+}
+
+// prebound es implementada por los builders que guardan su sql ya convertido a las marcas de su dialecto
+type prebound interface {
+	// rebindTo devuelve q con las marcas del dialecto d
+	rebindTo(d Dialect, q string) string
+}
+
+// around ejecuta exec envuelto por los hooks AroundExecute, el primero de hooks por fuera
+func around(ctx context.Context, st *Statement, hooks []Hook, exec func(ctx context.Context, st *Statement) error) error {
+	next := func(ctx context.Context) error {
+		st.executed = true
+		return exec(ctx, st)
+	}
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if h, inner := hooks[i].AroundExecute, next; h != nil {
+			next = func(ctx context.Context) error {
+				return h(ctx, st, inner)
+			}
+		}
+	}
+
+	if err := next(ctx); err != nil {
+		return err
+	}
+
+	if !st.executed {
+		return wrapQueryError(ErrNotExecuted, st.SQL, st.Params)
+	}
+
+	return nil
+}
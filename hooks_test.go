@@ -0,0 +1,139 @@
+package obreron
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type requestIDKey struct{}
+
+func TestHooksRewriteAndWrapExecution(t *testing.T) {
+	db, fdb := newFakeDB(t)
+	defer ResetHooks()
+
+	var trace []string
+
+	AddHook(Hook{
+		AfterBuild: func(ctx context.Context, st *Statement) error {
+			trace = append(trace, "global after")
+			st.SQL = "/* request_id=" + ctx.Value(requestIDKey{}).(string) + " */ " + st.SQL
+			return nil
+		},
+		AroundExecute: func(ctx context.Context, st *Statement, next func(context.Context) error) error {
+			trace = append(trace, "global before exec")
+			err := next(ctx)
+			trace = append(trace, "global after exec")
+			return err
+		},
+	})
+
+	b := NewBuilder(Postgres{}).Select("id").From("users", "u").Where().AndParam("status", "=", 1)
+	b.Hook(Hook{
+		BeforeBuild: func(ctx context.Context, bl Builder) error {
+			trace = append(trace, "builder before")
+			bl.(*Select).AndParam("deleted", "=", false)
+			return nil
+		},
+		AroundExecute: func(ctx context.Context, st *Statement, next func(context.Context) error) error {
+			trace = append(trace, "builder exec "+st.SQL)
+			return next(ctx)
+		},
+	})
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "abc")
+
+	if _, err := b.Exec(ctx, db); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	expected := "/* request_id=abc */ SELECT id FROM users u  WHERE 1=1  AND status = $1 AND deleted = $2"
+
+	if c := fdb.lastCall(); c.query != expected || !reflect.DeepEqual(c.args, []driver.Value{int64(1), false}) {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s %v", c.query, c.args)
+		t.FailNow()
+	}
+
+	expectedTrace := []string{"builder before", "global after", "global before exec", "builder exec " + expected, "global after exec"}
+
+	if !reflect.DeepEqual(trace, expectedTrace) {
+		t.Logf("expected : %q", expectedTrace)
+		t.Logf("generated: %q", trace)
+		t.FailNow()
+	}
+}
+
+func TestHooksReject(t *testing.T) {
+	db, fdb := newFakeDB(t)
+	defer ResetHooks()
+
+	errNoWhere := errors.New("UPDATE sin WHERE")
+
+	AddHook(Hook{
+		AfterBuild: func(ctx context.Context, st *Statement) error {
+			if strings.HasPrefix(st.SQL, "UPDATE users ") && !strings.Contains(st.SQL, " WHERE ") {
+				return errNoWhere
+			}
+			return nil
+		},
+	})
+
+	if _, err := Exec(context.Background(), db, NewMaryUpdate("users").Set("name", "ana")); !errors.Is(err, errNoWhere) {
+		t.Logf("expected errNoWhere, got %v", err)
+		t.FailNow()
+	}
+
+	if c := fdb.lastCall(); c.query != "" {
+		t.Logf("unexpected call %#v", c)
+		t.FailNow()
+	}
+
+	if _, err := Exec(context.Background(), db, NewMaryUpdate("users").Set("name", "ana").Where().AndParam("id", "=", 1)); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+}
+
+func TestHooksSkippingExecution(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	b := NewMaryBuilder().Select("id").From("users", "").Hook(Hook{
+		AroundExecute: func(ctx context.Context, st *Statement, next func(context.Context) error) error {
+			return nil
+		},
+	})
+
+	if _, err := b.Query(context.Background(), db); !errors.Is(err, ErrNotExecuted) {
+		t.Logf("expected ErrNotExecuted, got %v", err)
+		t.FailNow()
+	}
+
+	if err := b.QueryRow(context.Background(), db).Scan(); !errors.Is(err, ErrNotExecuted) {
+		t.Logf("expected ErrNotExecuted, got %v", err)
+		t.FailNow()
+	}
+}
+
+func TestBuildContext(t *testing.T) {
+	b := NewBuilder(Postgres{}).Select("id").From("users", "").Where().AndParam("id", "=", 1).Hook(Hook{
+		AfterBuild: func(ctx context.Context, st *Statement) error {
+			st.SQL += " AND tenant = ?"
+			st.Params = append(st.Params, 7)
+			return nil
+		},
+	})
+
+	q, p, err := BuildContext(context.Background(), b)
+	expected := "SELECT id FROM users WHERE 1=1  AND id = ? AND tenant = ?"
+
+	if err != nil || q != expected || !reflect.DeepEqual(p, []interface{}{1, 7}) {
+		t.Logf("expected : %s [1 7]", expected)
+		t.Logf("generated: %s %v %v", q, p, err)
+		t.FailNow()
+	}
+}
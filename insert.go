@@ -0,0 +1,165 @@
+package obreron
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// ErrEmptyStatement indica que la sentencia no tiene nada que escribir, como un INSERT sin filas o un UPDATE sin asignaciones
+var ErrEmptyStatement = errors.New("obreron: sentencia vacía")
+
+// Insert es el builder para sentencias INSERT
+type Insert struct {
+	columns []string
+	values  *SQLBuilder
+
+	*SQLBuilder
+
+	table string
+	err   error
+	hooks []Hook
+
+	q string
+}
+
+// NewMaryInsert devuelve un nuevo builder de INSERT para mysql sobre la tabla table
+func NewMaryInsert(table string) *Insert {
+	return NewInsert(Mysql{}, table)
+}
+
+// NewInsert devuelve un nuevo builder de INSERT con el dialecto d sobre la tabla table
+func NewInsert(d Dialect, table string) *Insert {
+	return &Insert{
+		values:     newSQLBuilder(d),
+		SQLBuilder: newSQLBuilder(d),
+		table:      table,
+	}
+}
+
+// Columns define las columnas a insertar. Debe llamarse antes de agregar filas con Values
+func (s *Insert) Columns(cols ...string) *Insert {
+	s.q = ""
+	s.columns = append(s.columns[:0], cols...)
+	return s
+}
+
+// Values agrega una fila con los valores vals como parámetros. Debe recibir tantos valores como columnas
+func (s *Insert) Values(vals ...interface{}) *Insert {
+	s.q = ""
+
+	if len(vals) != len(s.columns) {
+		s.setErr(fmt.Errorf("%w: %d valores para %d columnas", ErrInvalidRecord, len(vals), len(s.columns)))
+		return s
+	}
+
+	if s.values.Len() > 0 {
+		s.values.WriteByte(44)
+	}
+
+	s.values.WriteString(s.values.Dialect().OpenEnclose())
+	for i := range vals {
+		if i > 0 {
+			s.values.WriteByte(44)
+		}
+		s.values.WriteString(s.values.Dialect().ParamMark())
+	}
+	s.values.WriteString(s.values.Dialect().CloseEnclose())
+	s.values.AddParam(vals...)
+
+	return s
+}
+
+// Record agrega una fila a partir de v, que puede ser un struct o un map con llaves string.
+// De los structs se toman los campos según sus tags `db`, omitiendo los marcados `readonly` y los
+// `omitempty` con valor cero; las llaves de los maps se ordenan alfabéticamente.
+// La primera fila define las columnas si no se definieron con Columns; las siguientes deben tener las mismas
+func (s *Insert) Record(v interface{}) *Insert {
+	cols, params, err := recordValues(v, forInsert)
+	if err != nil {
+		s.setErr(err)
+		return s
+	}
+
+	if s.columns == nil {
+		s.Columns(cols...)
+	} else if !sameColumns(s.columns, cols) {
+		s.setErr(fmt.Errorf("%w: las columnas %v no calzan con %v", ErrInvalidRecord, cols, s.columns))
+		return s
+	}
+
+	return s.Values(params...)
+}
+
+// Err devuelve el primer error registrado al construir la sentencia, o ErrEmptyStatement si no tiene filas
+func (s *Insert) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if len(s.columns) == 0 || s.values.Len() == 0 {
+		return fmt.Errorf("%w: INSERT INTO %s sin filas", ErrEmptyStatement, s.table)
+	}
+
+	return nil
+}
+
+func (s *Insert) setErr(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// Params devuelve los parámetros de las filas a insertar
+func (s *Insert) Params() []interface{} {
+	return s.values.params
+}
+
+func (s *Insert) String() string {
+	if s.q != "" {
+		return s.q
+	}
+
+	if s.Len() > 0 {
+		// el sql anterior pudo haberse entregado, así que no se reutiliza su memoria
+		s.Buffer = bytes.Buffer{}
+	}
+
+	s.WriteString("INSERT INTO ")
+	s.WriteString(s.table)
+	s.WriteByte(' ')
+	s.WriteString(s.Dialect().OpenEnclose())
+	for i, c := range s.columns {
+		if i > 0 {
+			s.WriteByte(44)
+		}
+		s.WriteString(c)
+	}
+	s.WriteString(s.Dialect().CloseEnclose())
+	s.WriteString(" VALUES ")
+	s.Write(s.values.Bytes())
+
+	s.q = *(*string)(unsafe.Pointer(&s.Buffer))
+
+	return s.q
+}
+
+// Build construye la sentencia devolviendo una tupla conteniendola en un string y los parámetros
+// registrados para su uso
+func (s *Insert) Build() (string, []interface{}) {
+	return s.String(), s.Params()
+}
+
+// sameColumns indica si a y b tienen las mismas columnas en el mismo orden
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
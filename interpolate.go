@@ -0,0 +1,141 @@
+package obreron
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInterpolate indica que los parámetros no pudieron interpolarse en la consulta
+var ErrInterpolate = errors.New("obreron: no se pudo interpolar la consulta")
+
+// LiteralFormatter es implementada por los dialectos que escriben los literales de texto, binarios o de fecha
+// de una forma distinta al estándar sql. Solo se usa al interpolar parámetros, ver Interpolate
+type LiteralFormatter interface {
+	// FormatString devuelve s como literal de texto
+	FormatString(s string) string
+	// FormatBytes devuelve b como literal binario
+	FormatBytes(b []byte) string
+	// FormatTime devuelve t como literal de fecha
+	FormatTime(t time.Time) string
+}
+
+// Interpolate reemplaza las marcas `?` de q por los parámetros params escritos como literales del dialecto d.
+//
+// El resultado solo sirve para registrar o depurar consultas. NO es seguro ejecutarlo: el escape de los
+// literales no reemplaza a los parámetros del driver y no protege de inyecciones sql.
+//
+// Los valores se convierten como lo hace database/sql, por lo que se resuelven los driver.Valuer y los punteros.
+// nil se escribe como NULL, los textos entre comillas y escapados, los []byte en hexadecimal y los time.Time
+// con fecha y hora.
+func Interpolate(d Dialect, q string, params ...interface{}) (string, error) {
+	pos := placeholders(q, mysqlLexing(d))
+
+	if len(pos) != len(params) {
+		return "", fmt.Errorf("%w: %d marcas para %d parámetros", ErrInterpolate, len(pos), len(params))
+	}
+
+	if len(pos) == 0 {
+		return q, nil
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(q) + len(params)*8)
+
+	last := 0
+	for i, at := range pos {
+		lit, err := literal(d, params[i])
+		if err != nil {
+			return "", fmt.Errorf("%w: parámetro %d: %v", ErrInterpolate, i+1, err)
+		}
+
+		sb.WriteString(q[last:at])
+		sb.WriteString(lit)
+		last = at + 1
+	}
+	sb.WriteString(q[last:])
+
+	return sb.String(), nil
+}
+
+// Interpolate devuelve la consulta con sus parámetros escritos como literales del dialecto.
+// El resultado solo sirve para registrar o depurar la consulta y NO es seguro ejecutarlo, ver Interpolate
+func (s *Select) Interpolate() (string, error) {
+	q, params := s.Build()
+	return Interpolate(s.Dialect(), q, params...)
+}
+
+// DebugString es como Interpolate, pero si los parámetros no pueden interpolarse devuelve la consulta sin
+// interpolar seguida por sus parámetros. NO es seguro ejecutar el resultado
+func (s *Select) DebugString() string {
+	q, err := s.Interpolate()
+	if err != nil {
+		q, params := s.Build()
+		return fmt.Sprintf("%s -- %v", q, params)
+	}
+	return q
+}
+
+// literal escribe v como un literal del dialecto d
+func literal(d Dialect, v interface{}) (string, error) {
+	v, err := driver.DefaultParameterConverter.ConvertValue(v)
+	if err != nil {
+		return "", err
+	}
+
+	f, ok := d.(LiteralFormatter)
+	if !ok {
+		f = standardLiterals{}
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return "NULL", nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return f.FormatString(strconv.FormatFloat(v, 'g', -1, 64)), nil
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case string:
+		return f.FormatString(v), nil
+	case []byte:
+		if v == nil {
+			return "NULL", nil
+		}
+		return f.FormatBytes(v), nil
+	case time.Time:
+		return f.FormatTime(v), nil
+	}
+
+	return "", fmt.Errorf("tipo %T no soportado", v)
+}
+
+// standardLiterals escribe los literales según el estándar sql, y es usado por los dialectos que no
+// implementan LiteralFormatter
+type standardLiterals struct{}
+
+func (standardLiterals) FormatString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func (standardLiterals) FormatBytes(b []byte) string {
+	return "X'" + hex.EncodeToString(b) + "'"
+}
+
+func (standardLiterals) FormatTime(t time.Time) string {
+	return "'" + t.Format("2006-01-02 15:04:05.999999999-07:00") + "'"
+}
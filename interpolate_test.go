@@ -0,0 +1,109 @@
+package obreron
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// shout es un driver.Valuer de prueba
+type shout string
+
+func (u shout) Value() (driver.Value, error) {
+	return strings.ToUpper(string(u)), nil
+}
+
+func TestInterpolateLiterals(t *testing.T) {
+	at := time.Date(2024, 3, 5, 14, 7, 9, 120000000, time.FixedZone("", -3*3600))
+	name := "ana"
+	var missing *string
+
+	params := []interface{}{
+		nil, 42, uint8(7), 1.5, true, "o'brien", []byte{0xca, 0xfe}, at, shout("x"),
+		sql.NullString{}, &name, missing,
+	}
+
+	q := "SELECT 'literal ?' FROM t WHERE a=? AND b=? AND c=? AND d=? AND e=? AND f=? AND g=? AND h=? AND i=? AND j=? AND k=? AND l=?"
+
+	cases := []struct {
+		d        Dialect
+		expected string
+	}{
+		{
+			Mysql{},
+			`SELECT 'literal ?' FROM t WHERE a=NULL AND b=42 AND c=7 AND d=1.5 AND e=TRUE AND f='o\'brien' AND g=X'cafe' AND h='2024-03-05 14:07:09.12' AND i='X' AND j=NULL AND k='ana' AND l=NULL`,
+		},
+		{
+			Postgres{},
+			`SELECT 'literal ?' FROM t WHERE a=NULL AND b=42 AND c=7 AND d=1.5 AND e=TRUE AND f='o''brien' AND g='\xcafe' AND h='2024-03-05 14:07:09.12-03:00' AND i='X' AND j=NULL AND k='ana' AND l=NULL`,
+		},
+		{
+			Sqlite{},
+			`SELECT 'literal ?' FROM t WHERE a=NULL AND b=42 AND c=7 AND d=1.5 AND e=TRUE AND f='o''brien' AND g=X'cafe' AND h='2024-03-05 14:07:09.12-03:00' AND i='X' AND j=NULL AND k='ana' AND l=NULL`,
+		},
+	}
+
+	for _, c := range cases {
+		got, err := Interpolate(c.d, q, params...)
+
+		if err != nil || got != c.expected {
+			t.Logf("%T expected : %s", c.d, c.expected)
+			t.Logf("%T generated: %s %v", c.d, got, err)
+			t.FailNow()
+		}
+	}
+}
+
+func TestInterpolateMysqlEscapes(t *testing.T) {
+	got, err := Interpolate(Mysql{}, "SELECT ?", "a\\b'c\"d\ne\x00")
+	expected := `SELECT 'a\\b\'c\"d\ne\0'`
+
+	if err != nil || got != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s %v", got, err)
+		t.FailNow()
+	}
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	if _, err := Interpolate(Mysql{}, "SELECT ?, ?", 1); !errors.Is(err, ErrInterpolate) {
+		t.Logf("expected ErrInterpolate on missing params, got %v", err)
+		t.FailNow()
+	}
+
+	if _, err := Interpolate(Mysql{}, "SELECT ?", struct{}{}); !errors.Is(err, ErrInterpolate) {
+		t.Logf("expected ErrInterpolate on unsupported type, got %v", err)
+		t.FailNow()
+	}
+}
+
+func TestSelectInterpolate(t *testing.T) {
+	b := NewBuilder(Postgres{})
+	b.Select("id").From("users", "u").Where().AndParam("name", "=", "o'brien").AndParam("age", ">", 18)
+
+	expected := "SELECT id FROM users u  WHERE 1=1  AND name = 'o''brien' AND age > 18"
+
+	if q, err := b.Interpolate(); err != nil || q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s %v", q, err)
+		t.FailNow()
+	}
+
+	if q := b.DebugString(); q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+
+	b.AndParam("data", "=", struct{}{})
+	expected = "SELECT id FROM users u  WHERE 1=1  AND name = ? AND age > ? AND data = ? -- [o'brien 18 {}]"
+
+	if q := b.DebugString(); q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,85 @@
+package obreron
+
+// skipQuoted devuelve la posición siguiente al cierre del literal o identificador escapado que empieza
+// en q[i], cuyo caracter de cierre es q[i]. Las comillas duplicadas se consideran escapadas y, si backslash
+// es verdadero, también los caracteres precedidos por `\`
+func skipQuoted(q string, i int, backslash bool) int {
+	c := q[i]
+
+	for i++; i < len(q); i++ {
+		switch {
+		case backslash && q[i] == '\\' && c != '`':
+			i++
+		case q[i] == c:
+			if i+1 < len(q) && q[i+1] == c {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+
+	return len(q)
+}
+
+// mysqlLexing indica si el dialecto d sigue las reglas léxicas de mysql: `\` escapa caracteres dentro de los
+// literales y `#` inicia un comentario de línea. En postgresql, en cambio, `#` es un operador, como en `#>>`
+func mysqlLexing(d Dialect) bool {
+	_, ok := d.(Mysql)
+	return ok
+}
+
+// skipComment devuelve la posición siguiente al comentario que empieza en q[i], o i si no hay un comentario.
+// Si mysql es verdadero `#` también inicia un comentario de línea
+func skipComment(q string, i int, mysql bool) int {
+	if q[i] == '#' && mysql {
+		for ; i < len(q) && q[i] != '\n'; i++ {
+		}
+		return i
+	}
+
+	if i+1 >= len(q) {
+		return i
+	}
+
+	switch {
+	case q[i] == '-' && q[i+1] == '-':
+		for ; i < len(q) && q[i] != '\n'; i++ {
+		}
+		return i
+	case q[i] == '/' && q[i+1] == '*':
+		for i += 2; i+1 < len(q); i++ {
+			if q[i] == '*' && q[i+1] == '/' {
+				return i + 2
+			}
+		}
+		return len(q)
+	}
+
+	return i
+}
+
+// placeholders devuelve las posiciones de las marcas de parámetro `?` de q, ignorando las que estén
+// dentro de literales, identificadores escapados y comentarios. mysql indica si q sigue las reglas léxicas
+// de mysql, ver mysqlLexing
+func placeholders(q string, mysql bool) []int {
+	var pos []int
+
+	for i := 0; i < len(q); {
+		switch q[i] {
+		case '\'', '"', '`':
+			i = skipQuoted(q, i, mysql)
+			continue
+		case '-', '#', '/':
+			if j := skipComment(q, i, mysql); j > i {
+				i = j
+				continue
+			}
+		case '?':
+			pos = append(pos, i)
+		}
+		i++
+	}
+
+	return pos
+}
@@ -0,0 +1,150 @@
+package obreron
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidLock indica que la clausula de bloqueo está mal formada
+var ErrInvalidLock = errors.New("obreron: bloqueo inválido")
+
+// LockStrength es el tipo de bloqueo de las filas leídas
+type LockStrength string
+
+const (
+	// LockUpdate bloquea las filas para modificarlas, `FOR UPDATE`
+	LockUpdate = LockStrength("UPDATE")
+
+	// LockShare bloquea las filas solo para lectura, `FOR SHARE`. Requiere mysql 8 o postgresql
+	LockShare = LockStrength("SHARE")
+
+	// LockNoKeyUpdate es el `FOR NO KEY UPDATE` de postgresql
+	LockNoKeyUpdate = LockStrength("NO KEY UPDATE")
+
+	// LockKeyShare es el `FOR KEY SHARE` de postgresql
+	LockKeyShare = LockStrength("KEY SHARE")
+
+	// LockInShareMode es el `LOCK IN SHARE MODE` de mysql, anterior a FOR SHARE. No admite OF, NOWAIT ni SKIP LOCKED
+	LockInShareMode = LockStrength("LOCK IN SHARE MODE")
+)
+
+// LockWait indica qué hacer con las filas ya bloqueadas por otra transacción
+type LockWait int8
+
+const (
+	// LockWaitDefault espera a que las filas se liberen
+	LockWaitDefault = LockWait(0)
+
+	// LockNoWait falla en vez de esperar, `NOWAIT`
+	LockNoWait = LockWait(1)
+
+	// LockSkipLocked omite las filas bloqueadas, `SKIP LOCKED`
+	LockSkipLocked = LockWait(2)
+)
+
+// Lock es una clausula de bloqueo, como `FOR UPDATE OF jobs SKIP LOCKED`
+type Lock struct {
+	Strength LockStrength
+	// Of son las tablas a bloquear. Vacío bloquea todas las de la consulta
+	Of   []string
+	Wait LockWait
+}
+
+// Locker es implementada por los dialectos que soportan clausulas de bloqueo
+type Locker interface {
+	// SupportsLock indica si el dialecto soporta el tipo de bloqueo strength
+	SupportsLock(strength LockStrength) bool
+}
+
+// Lock agrega la clausula de bloqueo l, que se escribe después de LIMIT y OFFSET. Puede llamarse varias
+// veces para bloquear distintas tablas con distintos tipos de bloqueo. Si el dialecto no soporta el bloqueo,
+// Err devuelve ErrUnsupported
+func (s *Select) Lock(l Lock) *Select {
+	s.touch()
+
+	if lk, ok := s.Dialect().(Locker); !ok || !lk.SupportsLock(l.Strength) {
+		s.setErr(fmt.Errorf("%w: bloqueo %s", ErrUnsupported, l.Strength))
+		return s
+	}
+
+	// LOCK IN SHARE MODE debe ser el único bloqueo, sin importar si se agrega antes o después de los otros
+	shareMode := len(s.locks) > 0 && s.locks[0].Strength == LockInShareMode
+	if shareMode || (l.Strength == LockInShareMode && (len(l.Of) > 0 || l.Wait != LockWaitDefault || len(s.locks) > 0)) {
+		s.setErr(fmt.Errorf("%w: LOCK IN SHARE MODE no admite otras opciones de bloqueo", ErrInvalidLock))
+		return s
+	}
+
+	s.locks = append(s.locks, l)
+	return s
+}
+
+// ForUpdate agrega `FOR UPDATE`, limitado a las tablas of si se indican
+//
+//	b.Select("id").From("jobs", "").Where().And("status = 'pending'").Limit(10).ForUpdate().SkipLocked()
+func (s *Select) ForUpdate(of ...string) *Select {
+	return s.Lock(Lock{Strength: LockUpdate, Of: of})
+}
+
+// ForShare agrega `FOR SHARE`, limitado a las tablas of si se indican
+func (s *Select) ForShare(of ...string) *Select {
+	return s.Lock(Lock{Strength: LockShare, Of: of})
+}
+
+// LockInShareMode agrega el `LOCK IN SHARE MODE` de mysql
+func (s *Select) LockInShareMode() *Select {
+	return s.Lock(Lock{Strength: LockInShareMode})
+}
+
+// NoWait agrega NOWAIT al último bloqueo agregado
+func (s *Select) NoWait() *Select {
+	return s.lockWait(LockNoWait)
+}
+
+// SkipLocked agrega SKIP LOCKED al último bloqueo agregado
+func (s *Select) SkipLocked() *Select {
+	return s.lockWait(LockSkipLocked)
+}
+
+// lockWait cambia el LockWait del último bloqueo agregado
+func (s *Select) lockWait(w LockWait) *Select {
+	s.touch()
+
+	if len(s.locks) == 0 || s.locks[len(s.locks)-1].Strength == LockInShareMode {
+		s.setErr(fmt.Errorf("%w: NOWAIT y SKIP LOCKED requieren FOR UPDATE o FOR SHARE", ErrInvalidLock))
+		return s
+	}
+
+	s.locks[len(s.locks)-1].Wait = w
+	return s
+}
+
+// writeLocks escribe las clausulas de bloqueo
+func (s *Select) writeLocks() {
+	for _, l := range s.locks {
+		if l.Strength == LockInShareMode {
+			s.WriteString(" LOCK IN SHARE MODE ")
+			continue
+		}
+
+		s.WriteString(" FOR ")
+		s.WriteString(string(l.Strength))
+
+		for i, t := range l.Of {
+			if i == 0 {
+				s.WriteString(" OF ")
+			} else {
+				s.WriteByte(',')
+			}
+			s.WriteString(s.Quote(t))
+		}
+
+		switch l.Wait {
+		case LockNoWait:
+			s.WriteString(" NOWAIT")
+		case LockSkipLocked:
+			s.WriteString(" SKIP LOCKED")
+		}
+
+		s.WriteByte(' ')
+	}
+}
@@ -0,0 +1,70 @@
+package obreron
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLocking(t *testing.T) {
+	cases := []struct {
+		b        *Select
+		expected string
+	}{
+		{
+			NewBuilder(Postgres{}).Select("id").From("jobs", "").Where().AndParam("status", "=", "pending").
+				Limit(10).ForUpdate().SkipLocked(),
+			`SELECT id FROM jobs WHERE 1=1  AND status = ? LIMIT 10  FOR UPDATE SKIP LOCKED `,
+		},
+		{
+			NewBuilder(Postgres{}).Select("j.id").From("jobs", "j").Inner("queues", "q", "q.id = j.queue_id").
+				Limit(1).Offset(5).Lock(Lock{Strength: LockNoKeyUpdate, Of: []string{"j"}, Wait: LockNoWait}).ForShare("q"),
+			`SELECT j.id FROM jobs j  INNER JOIN queues q  ON q.id = j.queue_id LIMIT 1  OFFSET 5  FOR NO KEY UPDATE OF "j" NOWAIT  FOR SHARE OF "q" `,
+		},
+		{
+			NewMaryBuilder().Select("id").From("jobs", "").ForUpdate("jobs", "queues").NoWait(),
+			"SELECT id FROM jobs FOR UPDATE OF `jobs`,`queues` NOWAIT ",
+		},
+		{
+			NewMaryBuilder().Select("id").From("jobs", "").LockInShareMode().Comment("worker"),
+			"SELECT id FROM jobs LOCK IN SHARE MODE /* worker */ ",
+		},
+	}
+
+	for _, c := range cases {
+		if q := c.b.String(); q != c.expected || c.b.Err() != nil {
+			t.Logf("expected : %s", c.expected)
+			t.Logf("generated: %s %v", q, c.b.Err())
+			t.FailNow()
+		}
+	}
+}
+
+func TestLockingErrors(t *testing.T) {
+	cases := map[string]struct {
+		b    *Select
+		want error
+	}{
+		"sqlite":              {NewBuilder(Sqlite{}).Select("id").From("jobs", "").ForUpdate(), ErrUnsupported},
+		"mysql key share":     {NewMaryBuilder().Select("id").From("jobs", "").Lock(Lock{Strength: LockKeyShare}), ErrUnsupported},
+		"postgres share mode": {NewBuilder(Postgres{}).Select("id").From("jobs", "").LockInShareMode(), ErrUnsupported},
+		"wait without lock":   {NewMaryBuilder().Select("id").From("jobs", "").SkipLocked(), ErrInvalidLock},
+		"share mode nowait":   {NewMaryBuilder().Select("id").From("jobs", "").LockInShareMode().NoWait(), ErrInvalidLock},
+		"update after share":  {NewMaryBuilder().Select("id").From("jobs", "").LockInShareMode().ForUpdate(), ErrInvalidLock},
+		"share after update":  {NewMaryBuilder().Select("id").From("jobs", "").ForUpdate().LockInShareMode(), ErrInvalidLock},
+	}
+
+	for name, c := range cases {
+		if err := c.b.Err(); !errors.Is(err, c.want) {
+			t.Logf("%s: expected %v, got %v", name, c.want, err)
+			t.FailNow()
+		}
+	}
+
+	b := NewBuilder(Postgres{}).Select("id").From("jobs", "").ForUpdate()
+	b.Reset()
+
+	if q := b.Select("id").From("jobs", "").String(); q != "SELECT id FROM jobs" {
+		t.Logf("unexpected query after Reset: %s", q)
+		t.FailNow()
+	}
+}
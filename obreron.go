@@ -4,6 +4,7 @@ package obreron
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 	"unsafe"
 )
 
@@ -65,6 +66,7 @@ type SQLBuilder struct {
 	dialect Dialect
 	bytes.Buffer
 	params []interface{}
+	err    error
 }
 
 func newSQLBuilder(d Dialect) *SQLBuilder {
@@ -113,9 +115,10 @@ func (sb *SQLBuilder) Dialect() Dialect {
 	return sb.dialect
 }
 
-// ResetParams resetea el slices de parámetros
+// ResetParams resetea el slices de parámetros y el error registrado
 func (sb *SQLBuilder) ResetParams() {
 	sb.params = make([]interface{}, 0)
+	sb.err = nil
 }
 
 // Select es el builder para consultas que tienen datos
@@ -127,6 +130,7 @@ type Select struct {
 	source  *SQLBuilder
 	group   *SQLBuilder
 	having  *SQLBuilder
+	window  *SQLBuilder
 
 	*SQLBuilder
 
@@ -134,12 +138,37 @@ type Select struct {
 	offset int64
 
 	q string
+
+	// released indica que el builder fue devuelto al pool
+	released bool
+
+	// hooks son los hooks registrados solo para este builder
+	hooks []Hook
+
+	comments         []string
+	tags             map[string]string
+	commentPlacement CommentPlacement
+
+	optimizerHints []string
+	modifiers      selectModifier
+	distinctOn     []string
+
+	// hintTarget indica a qué tabla se aplican las pistas de índice, y joinHintAt dónde escribirlas si es un join
+	hintTarget int8
+	joinHintAt int
+
+	locks []Lock
+
+	err error
 }
 
 // NewMaryBuilder devuelve un nuevo sql builder listo para trabajar
 func NewMaryBuilder() *Select {
+	return NewBuilder(Mysql{})
+}
 
-	d := Mysql{}
+// NewBuilder devuelve un nuevo sql builder listo para trabajar con el dialecto d
+func NewBuilder(d Dialect) *Select {
 	s := Select{
 		columns:    newSQLBuilder(d),
 		joins:      newSQLBuilder(d),
@@ -148,6 +177,7 @@ func NewMaryBuilder() *Select {
 		source:     newSQLBuilder(d),
 		group:      newSQLBuilder(d),
 		having:     newSQLBuilder(d),
+		window:     newSQLBuilder(d),
 		SQLBuilder: newSQLBuilder(d),
 		limit:      -1,
 		offset:     -1,
@@ -155,7 +185,10 @@ func NewMaryBuilder() *Select {
 	return &s
 }
 
+// Reset deja el builder vacío, listo para construir otra consulta reutilizando su memoria.
+// El sql devuelto antes por String o Build deja de ser válido
 func (s *Select) Reset() {
+	s.mustBeAcquired()
 	s.columns.Reset()
 	s.joins.Reset()
 	s.filter.Reset()
@@ -163,22 +196,78 @@ func (s *Select) Reset() {
 	s.source.Reset()
 	s.group.Reset()
 	s.having.Reset()
+	s.window.Reset()
 	s.SQLBuilder.Reset()
-	s.columns.Reset()
+	s.columns.ResetParams()
 	s.joins.ResetParams()
 	s.filter.ResetParams()
 	s.order.ResetParams()
 	s.source.ResetParams()
 	s.group.ResetParams()
 	s.having.ResetParams()
+	s.window.ResetParams()
 	s.SQLBuilder.ResetParams()
+	s.resetOptions()
+}
+
+// resetOptions devuelve a sus valores por defecto las opciones de la consulta que no se guardan en los buffers
+func (s *Select) resetOptions() {
 	s.limit = -1
 	s.offset = -1
+	s.hooks = nil
+	s.comments = nil
+	s.tags = nil
+	s.commentPlacement = CommentSuffix
+	s.optimizerHints = nil
+	s.modifiers = 0
+	s.distinctOn = nil
+	s.hintTarget = hintNone
+	s.joinHintAt = 0
+	s.locks = nil
+	s.err = nil
+	s.q = ""
+}
+
+// Err devuelve el primer error registrado al construir la consulta, como el uso de una clausula que
+// el dialecto no soporta. Las consultas con error no se ejecutan
+func (s *Select) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	for _, sb := range s.builders() {
+		if sb.err != nil {
+			return sb.err
+		}
+	}
+
+	return nil
+}
+
+func (s *Select) setErr(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// touch invalida la consulta ya construida antes de modificar el builder
+func (s *Select) touch() {
+	s.mustBeAcquired()
+	s.q = ""
+}
+
+// mustBeAcquired entra en pánico si el builder ya fue devuelto al pool con Release
+func (s *Select) mustBeAcquired() {
+	if s.released {
+		panic(ErrUseAfterRelease)
+	}
 }
 
 // Params devuelve los paramétros registrados para los componentes de la consulta
 // en el orden esperado para las distintistas clausulas
 func (s *Select) Params() []interface{} {
+	s.mustBeAcquired()
+
 	sz := struct {
 		// size cantidad total de paramétros a recibir
 		size int
@@ -187,6 +276,10 @@ func (s *Select) Params() []interface{} {
 		ls int
 		lj int
 		lf int
+		lg int
+		lh int
+		lw int
+		lo int
 	}{}
 
 	if l := len(s.columns.params); l > 0 {
@@ -209,6 +302,26 @@ func (s *Select) Params() []interface{} {
 		sz.size += l
 	}
 
+	if l := len(s.group.params); l > 0 {
+		sz.lg = l
+		sz.size += l
+	}
+
+	if l := len(s.having.params); l > 0 {
+		sz.lh = l
+		sz.size += l
+	}
+
+	if l := len(s.window.params); l > 0 {
+		sz.lw = l
+		sz.size += l
+	}
+
+	if l := len(s.order.params); l > 0 {
+		sz.lo = l
+		sz.size += l
+	}
+
 	s.params = make([]interface{}, 0, sz.size)
 
 	if sz.lc > 0 {
@@ -226,26 +339,42 @@ func (s *Select) Params() []interface{} {
 	if sz.lf > 0 {
 		s.params = append(s.params, s.filter.params...)
 	}
+
+	if sz.lg > 0 {
+		s.params = append(s.params, s.group.params...)
+	}
+
+	if sz.lh > 0 {
+		s.params = append(s.params, s.having.params...)
+	}
+
+	if sz.lw > 0 {
+		s.params = append(s.params, s.window.params...)
+	}
+
+	if sz.lo > 0 {
+		s.params = append(s.params, s.order.params...)
+	}
 	return s.params
 }
 
 // Limit establece el limite de la consulta. Si este valor es -1 no se agregara la clausula OFFSET a la query construida
 func (s *Select) Limit(l int64) *Select {
-	s.q = ""
+	s.touch()
 	s.limit = l
 	return s
 }
 
 // Offset establece el offset de la consulta. Si este valor es -1 no se agregara la clausula OFFSET a la query construida
 func (s *Select) Offset(o int64) *Select {
-	s.q = ""
+	s.touch()
 	s.offset = o
 	return s
 }
 
 // Select define consultas para la consulta. Cada ve que se llama resetea el buffer de construción
 func (s *Select) Select(cs ...interface{}) *Select {
-	s.q = ""
+	s.touch()
 	s.columns.Reset()
 	// Para este parseo cerrar entre parenstesis solo a los builders, no escapar y no usar clausula AS
 	opt := newParsingOpts(EncloseOnlyBuilders, NoQuote, NoUseAs, "", "", "")
@@ -260,7 +389,7 @@ func (s *Select) Select(cs ...interface{}) *Select {
 
 // AddColumn agrega una columna con su alias. la columna c puede ser string u otro SQLBuilder. Puede omitir el alias pasando un string vacio
 func (s *Select) AddColumn(c interface{}, a string) *Select {
-	s.q = ""
+	s.touch()
 	// Para este parseo cerrar entre parenstesis solo a los builders, no escapar y no usar clausula AS
 	parse(s.columns, c, nil, newParsingOpts(EncloseOnlyBuilders, NoQuote, UseAs, a, "", ""))
 	s.columns.WriteByte(44)
@@ -277,39 +406,40 @@ func (s *Select) AddColumnIf(cond bool, c interface{}, a string) *Select {
 
 // From define el origen para obtener los datos de la consulta. Puede ser un string u otro sql builder
 func (s *Select) From(source interface{}, a string) *Select {
-	s.q = ""
+	s.touch()
 	// Para este parseo cerrar entre parenstesis solo a los builders, no escapar y usar clausula AS solo si se definio un alias
 	s.source.WriteString(" FROM ")
 	parse(s.source, source, nil, newParsingOpts(EncloseOnlyBuilders, NoQuote, NoUseAs, a, "", ""))
+	s.hintTarget = hintFrom
 	return s
 }
 
 // Inner Agrega un inner join a la construcción de la query. El joinable c puede ser string o un SQLBuilder
 // a es el alias, si no lo necesita puede pasarlo vacio.
-// on contiene la condición para la clausula on, puede dejarla vacia para que no se agregue
-func (s *Select) Inner(c interface{}, a string, on string) *Select {
+// on contiene la condición para la clausula on, como string o Raw con parámetros. Puede dejarla vacia para que no se agregue
+func (s *Select) Inner(c interface{}, a string, on interface{}) *Select {
 	return s.join(" INNER JOIN ", c, a, on)
 }
 
 // Left Agrega un left join a la construcción de la query. El joinable c puede ser string o un SQLBuilder
 // a es el alias, si no lo necesita puede pasarlo vacio.
-// on contiene la condición para la clausula on, puede dejarla vacia para que no se agregue
-func (s *Select) Left(c interface{}, a string, on string) *Select {
+// on contiene la condición para la clausula on, como string o Raw con parámetros. Puede dejarla vacia para que no se agregue
+func (s *Select) Left(c interface{}, a string, on interface{}) *Select {
 	return s.join(" LEFT JOIN ", c, a, on)
 }
 
 // Right Agrega un right join a la construcción de la query. El joinable c puede ser string o un SQLBuilder
 // a es el alias, si no lo necesita puede pasarlo vacio.
-// on contiene la condición para la clausula on, puede dejarla vacia para que no se agregue
-func (s *Select) Right(c interface{}, a string, on string) *Select {
+// on contiene la condición para la clausula on, como string o Raw con parámetros. Puede dejarla vacia para que no se agregue
+func (s *Select) Right(c interface{}, a string, on interface{}) *Select {
 	return s.join(" RIGHT JOIN ", c, a, on)
 }
 
 // RightIF Agrega un right join a la construcción de la query si la condición `cond` es verdadera.
 // El joinable c puede ser string o un SQLBuilder
 // a es el alias, si no lo necesita puede pasarlo vacio.
-// on contiene la condición para la clausula on, puede dejarla vacia para que no se agregue
-func (s *Select) RightIF(cond bool, c interface{}, a string, on string) *Select {
+// on contiene la condición para la clausula on, como string o Raw con parámetros. Puede dejarla vacia para que no se agregue
+func (s *Select) RightIF(cond bool, c interface{}, a string, on interface{}) *Select {
 	if cond {
 		return s.join(" RIGHT JOIN ", c, a, on)
 	}
@@ -319,8 +449,8 @@ func (s *Select) RightIF(cond bool, c interface{}, a string, on string) *Select
 // InnerIf Agrega un inner join a la construcción de la query si la condición `cond` es verdadera.
 // El joinable c puede ser string o un SQLBuilder
 // a es el alias, si no lo necesita puede pasarlo vacio.
-// on contiene la condición para la clausula on, puede dejarla vacia para que no se agregue
-func (s *Select) InnerIf(cond bool, c interface{}, a string, on string) *Select {
+// on contiene la condición para la clausula on, como string o Raw con parámetros. Puede dejarla vacia para que no se agregue
+func (s *Select) InnerIf(cond bool, c interface{}, a string, on interface{}) *Select {
 	if cond {
 		return s.join(" INNER JOIN ", c, a, on)
 	}
@@ -330,8 +460,8 @@ func (s *Select) InnerIf(cond bool, c interface{}, a string, on string) *Select
 // LeftIf Agrega un left join a la construcción de la query si la condición `cond` es verdadera.
 // El joinable c puede ser string o un SQLBuilder
 // a es el alias, si no lo necesita puede pasarlo vacio.
-// on contiene la condición para la clausula on, puede dejarla vacia para que no se agregue
-func (s *Select) LeftIf(cond bool, c interface{}, a string, on string) *Select {
+// on contiene la condición para la clausula on, como string o Raw con parámetros. Puede dejarla vacia para que no se agregue
+func (s *Select) LeftIf(cond bool, c interface{}, a string, on interface{}) *Select {
 	if cond {
 		return s.join(" LEFT JOIN ", c, a, on)
 	}
@@ -339,38 +469,98 @@ func (s *Select) LeftIf(cond bool, c interface{}, a string, on string) *Select {
 }
 
 // join es un método helper privado que ayuda a la construcción de joines
-func (s *Select) join(j string, c interface{}, a string, on string) *Select {
-	s.q = ""
+func (s *Select) join(j string, c interface{}, a string, on interface{}) *Select {
+	s.touch()
 	s.joins.WriteString(j)
-	// Para este parseo cerrar entre parenstesis solo a los builders, no escapar y no usar clausula AS usando alias solo si se definio, pasando el on
-	parse(s.joins, c, "", newParsingOpts(EncloseOnlyBuilders, NoQuote, NoUseAs, a, "", on))
+	// Para este parseo cerrar entre parenstesis solo a los builders, no escapar y no usar clausula AS usando alias solo si se definio.
+	// El on se escribe aparte para poder agregar pistas de índice antes de él
+	parse(s.joins, c, "", newParsingOpts(EncloseOnlyBuilders, NoQuote, NoUseAs, a, "", ""))
+	s.hintTarget = hintJoin
+	s.joinHintAt = s.joins.Len()
+
+	if !emptyOn(on) {
+		s.joins.WriteString(" ON ")
+		// Para este parseo no cerrar entre parentesis, no escapar y no usar clausula AS
+		parse(s.joins, on, nil, newParsingOpts(NoEnclose, NoQuote, NoUseAs, "", "", ""))
+	}
 	return s
 }
 
+// emptyOn indica si la condición on de un join está vacía, en cuyo caso no se escribe la clausula ON
+func emptyOn(on interface{}) bool {
+	switch on := on.(type) {
+	case nil:
+		return true
+	case string:
+		return on == ""
+	case RawSQL:
+		return on.sql == "" && len(on.args) == 0
+	}
+	return false
+}
+
 // GroupBy agrega la clausula GROUP BY al Sql Builder
 func (s *Select) GroupBy(c string) *Select {
-	s.q = ""
-	s.group.WriteString(fmt.Sprintf(" GROUP BY %v ", c))
+	s.touch()
+	s.group.WriteString(" GROUP BY ")
+	s.group.WriteString(c)
+	s.group.WriteByte(' ')
+	return s
+}
+
+// GroupByExpr agrega la clausula GROUP BY con la expresión c, como Raw, junto con sus parámetros
+func (s *Select) GroupByExpr(c interface{}) *Select {
+	s.touch()
+	s.group.WriteString(" GROUP BY ")
+	// Para este parseo no cerrar entre parentesis, no escapar y no usar clausula AS
+	parse(s.group, c, nil, newParsingOpts(NoEnclose, NoQuote, NoUseAs, "", "", ""))
+	s.group.WriteByte(' ')
 	return s
 }
 
 // Having agrega la clausula HAVING al Sql Builder
 func (s *Select) Having(c string) *Select {
-	s.q = ""
-	s.group.WriteString(fmt.Sprintf(" HAVING %v ", c))
+	s.touch()
+	s.group.WriteString(" HAVING ")
+	s.group.WriteString(c)
+	s.group.WriteByte(' ')
+	return s
+}
+
+// HavingExpr agrega la clausula HAVING con la expresión c, como Raw, junto con sus parámetros
+//
+//	b.Select("user_id").From("orders", "").GroupBy("user_id").HavingExpr(Raw("COUNT(*) > ?", 3))
+func (s *Select) HavingExpr(c interface{}) *Select {
+	s.touch()
+	s.group.WriteString(" HAVING ")
+	// Para este parseo no cerrar entre parentesis, no escapar y no usar clausula AS
+	parse(s.group, c, nil, newParsingOpts(NoEnclose, NoQuote, NoUseAs, "", "", ""))
+	s.group.WriteByte(' ')
 	return s
 }
 
 // OrderBy agrega la clausula ORDER BY al Sql Builder
 func (s *Select) OrderBy(c string) *Select {
-	s.q = ""
-	s.order.WriteString(fmt.Sprintf(" ORDER BY %v ", c))
+	s.touch()
+	s.order.WriteString(" ORDER BY ")
+	s.order.WriteString(c)
+	s.order.WriteByte(' ')
+	return s
+}
+
+// OrderByExpr agrega la clausula ORDER BY con la expresión c, como Case, junto con sus parámetros
+func (s *Select) OrderByExpr(c interface{}) *Select {
+	s.touch()
+	s.order.WriteString(" ORDER BY ")
+	// Para este parseo no cerrar entre parentesis, no escapar y no usar clausula AS
+	parse(s.order, c, nil, newParsingOpts(NoEnclose, NoQuote, NoUseAs, "", "", ""))
+	s.order.WriteByte(' ')
 	return s
 }
 
 // Where inicializa la clausula where
 func (s *Select) Where() *Select {
-	s.q = ""
+	s.touch()
 	s.filter.Reset()
 
 	s.filter.WriteString(" WHERE 1=1 ")
@@ -381,7 +571,7 @@ func (s *Select) Where() *Select {
 // c puede ser la condición como string o como un SQLBuilder
 // op es el operador y param el parámetro de la condición
 func (s *Select) AndParam(c interface{}, op string, param interface{}) *Select {
-	s.q = ""
+	s.touch()
 	s.filter.WriteString(" AND ")
 	// Para este parseo cerrar entre parenstesis solo a los builders, no escapar y no usar clausula AS ni  alias
 	parse(s.filter, c, param, newParsingOpts(EncloseOnlyBuilders, NoQuote, NoUseAs, "", op, ""))
@@ -400,15 +590,15 @@ func (s *Select) AndParamIf(cond bool, c interface{}, op string, param interface
 }
 
 // And Agrega una condición usando conector AND
-// c es un strig conteniendo la condición completa
-func (s *Select) And(c string) *Select {
+// c es un string conteniendo la condición completa, o un Raw con sus parámetros
+func (s *Select) And(c interface{}) *Select {
 	s.AndParam(c, "", nil)
 	return s
 }
 
 // AndIf Agrega una condición usando conector AND solo si cond es true
-// c es un strig conteniendo la condición completa
-func (s *Select) AndIf(cond bool, c string) *Select {
+// c es un string conteniendo la condición completa, o un Raw con sus parámetros
+func (s *Select) AndIf(cond bool, c interface{}) *Select {
 	if cond {
 		s.AndParam(c, "", nil)
 	}
@@ -419,7 +609,7 @@ func (s *Select) AndIf(cond bool, c string) *Select {
 // c puede ser la condición como string o como un SQLBuilder
 // op es el operador y param el parámetro de la condición
 func (s *Select) OrParam(c interface{}, op string, param interface{}) *Select {
-	s.q = ""
+	s.touch()
 	s.filter.WriteString(" OR ")
 	// Para este parseo cerrar entre parenstesis solo a los builders, no escapar y no usar clausula AS ni  alias
 	parse(s.filter, c, param, newParsingOpts(EncloseOnlyBuilders, NoQuote, NoUseAs, "", op, ""))
@@ -431,6 +621,12 @@ func (s *Select) Quote(c interface{}) string {
 	return s.SQLBuilder.dialect.Quote(c)
 }
 
+// QuoteIdent escapa el identificador ident según el dialecto de la consulta,
+// devolviendo un error si no puede escaparse de forma segura
+func (s *Select) QuoteIdent(ident string) (string, error) {
+	return QuoteIdent(s.SQLBuilder.dialect, ident)
+}
+
 // OpenEnclose Agrega un abre parentesis ( la consulta
 func (s *Select) OpenEnclose() string {
 	return s.SQLBuilder.dialect.OpenEnclose()
@@ -442,11 +638,25 @@ func (s *Select) CloseEnclose() string {
 }
 
 func (s *Select) String() string {
+	s.mustBeAcquired()
+
 	if s.q != "" {
 		return s.q
 	}
 
+	if n := s.SQLBuilder.Len(); n > 0 {
+		// el sql anterior pudo haberse entregado, así que no se reutiliza su memoria. El nuevo buffer se
+		// reserva del tamaño del anterior para asignar memoria una sola vez
+		s.SQLBuilder.Buffer = bytes.Buffer{}
+		s.SQLBuilder.Grow(n)
+	}
+
+	if s.commentPlacement == CommentPrefix {
+		s.writeComments()
+	}
+
 	s.WriteString("SELECT ")
+	s.writeModifiers()
 
 	if s.columns.Len() > 1 {
 		s.Write(s.columns.Bytes()[0 : s.columns.Len()-1])
@@ -467,16 +677,37 @@ func (s *Select) String() string {
 		s.Write(s.having.Bytes())
 	}
 
+	if s.window.Len() > 0 {
+		s.Write(s.window.Bytes())
+		s.WriteByte(' ')
+	}
+
 	if s.order.Len() > 0 {
 		s.Write(s.order.Bytes())
 	}
 
+	// los números se escriben en un arreglo en el stack para no asignar memoria
+	var num [20]byte
+
 	if s.limit > -1 {
-		s.WriteString(fmt.Sprintf(" LIMIT %v ", s.limit))
+		s.WriteString(" LIMIT ")
+		s.Write(strconv.AppendInt(num[:0], s.limit, 10))
+		s.WriteByte(' ')
 	}
 
 	if s.offset > -1 {
-		s.WriteString(fmt.Sprintf(" OFFSET %v ", s.offset))
+		s.WriteString(" OFFSET ")
+		s.Write(strconv.AppendInt(num[:0], s.offset, 10))
+		s.WriteByte(' ')
+	}
+
+	s.writeLocks()
+
+	if s.commentPlacement == CommentSuffix && (len(s.comments) > 0 || len(s.tags) > 0) {
+		if b := s.Bytes(); b[len(b)-1] != ' ' {
+			s.WriteByte(' ')
+		}
+		s.writeComments()
 	}
 
 	s.q = *(*string)(unsafe.Pointer(&s.Buffer))
@@ -497,6 +728,7 @@ func parse(subject *SQLBuilder, circumstance interface{}, parameter interface{},
 
 	switch circumstance.(type) {
 	default:
+		subject.setErr(fmt.Errorf("%w: tipo %T no soportado", ErrInvalidExpression, circumstance))
 		return subject
 	case string:
 		parseString(subject, circumstance, parameter, opt)
@@ -504,6 +736,8 @@ func parse(subject *SQLBuilder, circumstance interface{}, parameter interface{},
 		parseBuilder(subject, circumstance, parameter, opt)
 	case *Select:
 		parseSelect(subject, circumstance, parameter, opt)
+	case Expression:
+		parseExpression(subject, circumstance, parameter, opt)
 	}
 
 	closeHook(subject, circumstance, parameter, opt)
@@ -578,6 +812,8 @@ func parseSelect(subject *SQLBuilder, circumstance interface{}, parameter interf
 	}
 
 	subject.AddParam(smt.Params()...)
+	// el error de la subconsulta impide ejecutar la consulta que la contiene
+	subject.setErr(smt.Err())
 }
 
 // openHook concentra el proceso antes del parseo
@@ -596,14 +832,17 @@ func closeHook(subject *SQLBuilder, circumstance interface{}, parameter interfac
 
 	if opt.Alias != "" {
 		if opt.UseAS {
-			_, _ = subject.WriteString(fmt.Sprintf(" AS %v ", opt.Alias))
+			_, _ = subject.WriteString(" AS ")
 		} else {
-			_, _ = subject.WriteString(fmt.Sprintf(" %v ", opt.Alias))
+			_ = subject.WriteByte(' ')
 		}
+		_, _ = subject.WriteString(opt.Alias)
+		_ = subject.WriteByte(' ')
 	}
 
 	if opt.On != "" {
-		_, _ = subject.WriteString(fmt.Sprintf(" ON %v", opt.On))
+		_, _ = subject.WriteString(" ON ")
+		_, _ = subject.WriteString(opt.On)
 
 		// este if es para agregar los posibles parametros en una clausula on
 		if parameter != nil && parameter != "" {
@@ -612,7 +851,9 @@ func closeHook(subject *SQLBuilder, circumstance interface{}, parameter interfac
 	}
 
 	if opt.Operator != "" {
-		_, _ = subject.WriteString(fmt.Sprintf(" %v ", opt.Operator))
+		_ = subject.WriteByte(' ')
+		_, _ = subject.WriteString(opt.Operator)
+		_ = subject.WriteByte(' ')
 	}
 
 	// // este if es para agregar los posibles parametros en una clausula WHERE
@@ -355,3 +355,79 @@ func testWhere(t testing.TB) (string, []interface{}) {
 
 	return b.Build()
 }
+
+// renderClauses construye una consulta que pasa por todas las clausulas que escribe el builder
+func renderClauses(bl *Select) string {
+	bl.Reset()
+	return bl.Select("u.id", "u.name").AddColumn("count(*)", "total").From("users", "u").
+		Inner("roles", "r", "r.user_id = u.id").
+		Where().And("u.active = 1").
+		GroupBy("u.id, u.name").Having("count(*) > 1").OrderBy("u.name ASC").
+		Limit(10).Offset(20).String()
+}
+
+func TestRenderClauses(t *testing.T) {
+	bl := NewMaryBuilder()
+	q := renderClauses(bl)
+
+	expected := "SELECT u.id,u.name,count(*) AS total  FROM users u  INNER JOIN roles r  ON r.user_id = u.id WHERE 1=1  AND u.active = 1 GROUP BY u.id, u.name  HAVING count(*) > 1  ORDER BY u.name ASC  LIMIT 10  OFFSET 20 "
+
+	if q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+
+	// al modificar el builder ya construido la consulta debe construirse de nuevo desde cero
+	expected = "SELECT u.id,u.name,count(*) AS total  FROM users u  INNER JOIN roles r  ON r.user_id = u.id WHERE 1=1  AND u.active = 1 GROUP BY u.id, u.name  HAVING count(*) > 1  ORDER BY u.name ASC  LIMIT 5  OFFSET 20 "
+
+	first := q
+
+	if q = bl.Limit(5).String(); q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+
+	// el sql entregado antes no debe cambiar al construir de nuevo
+	if expected = "SELECT u.id,u.name,count(*) AS total  FROM users u  INNER JOIN roles r  ON r.user_id = u.id WHERE 1=1  AND u.active = 1 GROUP BY u.id, u.name  HAVING count(*) > 1  ORDER BY u.name ASC  LIMIT 10  OFFSET 20 "; first != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", first)
+		t.FailNow()
+	}
+}
+
+func TestRenderDoesNotAllocate(t *testing.T) {
+	bl := NewMaryBuilder()
+	_ = renderClauses(bl)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = renderClauses(bl)
+	})
+
+	if allocs != 0 {
+		t.Logf("expected 0 allocs building over a reused builder, got %v", allocs)
+		t.FailNow()
+	}
+
+	// construir de nuevo tras un cambio solo asigna el buffer nuevo, para no pisar el sql ya entregado
+	allocs = testing.AllocsPerRun(100, func() {
+		_ = bl.Limit(10).String()
+	})
+
+	if allocs > 1 {
+		t.Logf("expected at most 1 alloc rendering again, got %v", allocs)
+		t.FailNow()
+	}
+}
+
+func BenchmarkRenderClauses(b *testing.B) {
+	bl := NewMaryBuilder()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = renderClauses(bl)
+	}
+}
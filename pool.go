@@ -0,0 +1,118 @@
+package obreron
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// MaxPooledBufferSize es la capacidad máxima, en bytes, que puede conservar cada buffer de un builder
+// devuelto al pool. Los buffers más grandes se descartan para que una consulta gigante no retenga memoria
+const MaxPooledBufferSize = 64 << 10
+
+// maxPooledParams es la capacidad máxima que puede conservar cada slice de parámetros de un builder devuelto al pool
+const maxPooledParams = 1024
+
+// ErrUseAfterRelease es el valor del pánico provocado al usar un builder después de devolverlo con Release
+var ErrUseAfterRelease = errors.New("obreron: builder usado después de Release")
+
+var selectPool = sync.Pool{
+	New: func() interface{} {
+		return NewMaryBuilder()
+	},
+}
+
+// poolDebug indica si el pool está en modo de depuración
+var poolDebug atomic.Bool
+
+// SetPoolDebug activa o desactiva el modo de depuración del pool. En este modo los builders devueltos con
+// Release no se reutilizan, de forma que cualquier uso posterior provoca un pánico con ErrUseAfterRelease
+// en vez de corromper la consulta de otro usuario del pool
+func SetPoolDebug(on bool) {
+	poolDebug.Store(on)
+}
+
+// Acquire obtiene del pool un builder para mysql, vacío y listo para trabajar. Debe devolverse con Release
+// cuando ya no se use
+//
+//	b := obreron.Acquire()
+//	defer obreron.Release(b)
+func Acquire() *Select {
+	return AcquireBuilder(Mysql{})
+}
+
+// AcquireBuilder obtiene del pool un builder para el dialecto d, vacío y listo para trabajar.
+// Debe devolverse con Release cuando ya no se use
+func AcquireBuilder(d Dialect) *Select {
+	s := selectPool.Get().(*Select)
+	s.released = false
+	s.setDialect(d)
+	return s
+}
+
+// Release devuelve al pool un builder obtenido con Acquire. Después de llamarlo, ni el builder ni el sql
+// devuelto por su String o Build deben usarse; los parámetros devueltos por Params o Build siguen siendo válidos.
+func Release(s *Select) {
+	if s == nil {
+		return
+	}
+
+	s.mustBeAcquired()
+
+	if poolDebug.Load() {
+		s.released = true
+		return
+	}
+
+	s.recycle()
+	s.released = true
+
+	selectPool.Put(s)
+}
+
+// recycle vacía el builder para devolverlo al pool, descartando los buffers y parámetros demasiado grandes
+func (s *Select) recycle() {
+	// los parámetros del builder externo son el slice entregado por Params, que puede seguir en uso
+	s.SQLBuilder.params = nil
+
+	for _, sb := range s.builders() {
+		sb.recycle()
+	}
+
+	s.resetOptions()
+}
+
+// builders devuelve los builders internos del Select
+func (s *Select) builders() []*SQLBuilder {
+	return []*SQLBuilder{s.columns, s.joins, s.filter, s.order, s.source, s.group, s.having, s.window, s.SQLBuilder}
+}
+
+// setDialect cambia el dialecto del Select y sus builders internos
+func (s *Select) setDialect(d Dialect) {
+	for _, sb := range s.builders() {
+		sb.dialect = d
+	}
+}
+
+// recycle vacía el SQLBuilder conservando su memoria, salvo que exceda los límites del pool
+func (sb *SQLBuilder) recycle() {
+	sb.err = nil
+
+	if sb.Cap() > MaxPooledBufferSize {
+		sb.Buffer = bytes.Buffer{}
+	} else {
+		sb.Reset()
+	}
+
+	if cap(sb.params) > maxPooledParams {
+		sb.params = nil
+		return
+	}
+
+	// se limpian las referencias para no retener los valores de los parámetros
+	for i := range sb.params {
+		sb.params[i] = nil
+	}
+	sb.params = sb.params[:0]
+}
@@ -0,0 +1,99 @@
+package obreron
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPoolReuse(t *testing.T) {
+	b := Acquire()
+	b.Select("a").From("t", "").Where().AndParam("x", "=", 1).Limit(10)
+	Release(b)
+
+	b = AcquireBuilder(Postgres{})
+	defer Release(b)
+
+	q, p := b.Select("user_id").From("users", "u").Where().AndParam("user_status", "=", 0).Build()
+	expected := "SELECT user_id FROM users u  WHERE 1=1  AND user_status = ?"
+
+	if q != expected || len(p) != 1 || p[0] != 0 {
+		t.Logf("expected : %s [0]", expected)
+		t.Logf("generated: %s %v", q, p)
+		t.FailNow()
+	}
+
+	if _, ok := b.Dialect().(Postgres); !ok {
+		t.Logf("expected Postgres dialect, got %T", b.Dialect())
+		t.FailNow()
+	}
+}
+
+func TestPoolUseAfterRelease(t *testing.T) {
+	SetPoolDebug(true)
+	defer SetPoolDebug(false)
+
+	cases := map[string]func(*Select){
+		"Select":  func(s *Select) { s.Select("a") },
+		"Where":   func(s *Select) { s.Where() },
+		"String":  func(s *Select) { _ = s.String() },
+		"Params":  func(s *Select) { _ = s.Params() },
+		"Release": func(s *Select) { Release(s) },
+	}
+
+	for name, use := range cases {
+		b := Acquire()
+		b.Select("a").From("t", "")
+		Release(b)
+
+		func() {
+			defer func() {
+				if r := recover(); r != ErrUseAfterRelease {
+					t.Logf("%s: expected panic %v, got %v", name, ErrUseAfterRelease, r)
+					t.FailNow()
+				}
+			}()
+			use(b)
+		}()
+	}
+}
+
+func TestPoolDropsLargeBuffers(t *testing.T) {
+	b := Acquire()
+	b.Select(strings.Repeat("a", MaxPooledBufferSize+1)).From("t", "")
+	_ = b.String()
+
+	b.recycle()
+
+	for i, sb := range b.builders() {
+		if sb.Cap() > MaxPooledBufferSize {
+			t.Logf("builder %d kept a buffer of %d bytes", i, sb.Cap())
+			t.FailNow()
+		}
+	}
+}
+
+func TestPoolKeepsReturnedParams(t *testing.T) {
+	b := Acquire()
+	_, p := b.Select("id").From("users", "").Where().AndParam("status", "=", 1).AndParam("kind", "=", "a").Build()
+	Release(b)
+
+	// el siguiente uso del pool no debe pisar los parámetros entregados
+	next := Acquire()
+	_, _ = next.Select("id").From("roles", "").Where().AndParam("level", "=", 9).Build()
+	defer Release(next)
+
+	if !reflect.DeepEqual(p, []interface{}{1, "a"}) {
+		t.Logf("expected the params to survive Release, got %v", p)
+		t.FailNow()
+	}
+}
+
+func BenchmarkHeavyQueryPooled(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		bl := Acquire()
+		heavyQueryBuild(b, bl)
+		Release(bl)
+	}
+}
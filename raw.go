@@ -0,0 +1,32 @@
+package obreron
+
+import "fmt"
+
+var _ Expression = RawSQL{}
+
+// RawSQL es un fragmento de sql que se escribe tal cual junto con los parámetros de sus marcas, ver Raw
+type RawSQL struct {
+	sql  string
+	args []interface{}
+}
+
+// Raw devuelve el fragmento sql con los parámetros args de sus marcas `?`, en el orden en que aparecen.
+// Puede usarse como columna, origen, join, clausula ON o condición, y en GROUP BY, HAVING y ORDER BY con
+// GroupByExpr, HavingExpr y OrderByExpr. Un Raw vacío en la clausula ON equivale a omitirla
+//
+//	b.Select(Raw("? AS diez", 10)).From("users", "u").Inner("roles", "r", Raw("r.id = u.role_id AND r.level > ?", 3))
+func Raw(sql string, args ...interface{}) RawSQL {
+	return RawSQL{sql: sql, args: args}
+}
+
+// ToSQL escribe el fragmento en w junto con sus parámetros. Devuelve ErrInvalidExpression si la cantidad
+// de marcas no coincide con la de parámetros
+func (r RawSQL) ToSQL(d Dialect, w *SQLBuilder) error {
+	if n := len(placeholders(r.sql, mysqlLexing(d))); n != len(r.args) {
+		return fmt.Errorf("%w: %q tiene %d marcas y %d parámetros", ErrInvalidExpression, r.sql, n, len(r.args))
+	}
+
+	w.WriteString(r.sql)
+	w.AddParam(r.args...)
+	return nil
+}
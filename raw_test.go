@@ -0,0 +1,88 @@
+package obreron
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRaw(t *testing.T) {
+	b := NewMaryBuilder().Select("u.id", Raw("? AS diez", 10)).
+		AddColumn(Raw("COALESCE(u.nick, ?)", "anon"), "nick").
+		From(Raw("(SELECT * FROM users WHERE tenant = ?)", 7), "u").
+		Inner("roles", "r", Raw("r.id = u.role_id AND r.level > ?", 3)).
+		Left(Raw("groups_for(?)", "x"), "g", "g.id = u.group_id").
+		Where().And(Raw("u.created_at > ? AND u.name <> '?'", "2024-01-01")).
+		AndParam(Raw("LENGTH(u.name) + ?", 1), ">", 5).
+		AndIf(true, Raw("u.status IN (?, ?)", 1, 2))
+
+	expected := "SELECT u.id,? AS diez,COALESCE(u.nick, ?) AS nick  FROM (SELECT * FROM users WHERE tenant = ?) u  INNER JOIN roles r  ON r.id = u.role_id AND r.level > ? LEFT JOIN groups_for(?) g  ON g.id = u.group_id WHERE 1=1  AND u.created_at > ? AND u.name <> '?' AND LENGTH(u.name) + ? > ? AND u.status IN (?, ?)"
+
+	q, params := b.Build()
+
+	if q != expected || b.Err() != nil {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s %v", q, b.Err())
+		t.FailNow()
+	}
+
+	if expected := []interface{}{10, "anon", 7, 3, "x", "2024-01-01", 1, 5, 1, 2}; !reflect.DeepEqual(params, expected) {
+		t.Logf("expected params %v, got %v", expected, params)
+		t.FailNow()
+	}
+
+	u := NewMaryUpdate("users").Set("name", "ana").Where().And(Raw("id = ?", 4))
+
+	if q, params := u.Build(); q != "UPDATE users SET name = ? WHERE 1=1  AND id = ?" || !reflect.DeepEqual(params, []interface{}{"ana", 4}) {
+		t.Logf("unexpected update: %s %v", q, params)
+		t.FailNow()
+	}
+}
+
+func TestRawGroupingAndEmptyOn(t *testing.T) {
+	b := NewMaryBuilder().Select("u.id", "COUNT(*)").From("users", "u").
+		Inner("roles", "r", Raw("")).
+		Where().AndParam("u.status", "=", 1).
+		GroupByExpr(Raw("u.id, DATE_FORMAT(u.created_at, ?)", "%Y")).
+		HavingExpr(Raw("COUNT(*) > ?", 3)).
+		OrderByExpr(Raw("FIELD(u.kind, ?, ?)", "a", "b")).
+		Limit(10)
+
+	expected := "SELECT u.id,COUNT(*) FROM users u  INNER JOIN roles r  WHERE 1=1  AND u.status = ? GROUP BY u.id, DATE_FORMAT(u.created_at, ?)  HAVING COUNT(*) > ?  ORDER BY FIELD(u.kind, ?, ?)  LIMIT 10 "
+
+	q, params := b.Build()
+
+	if q != expected || b.Err() != nil {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s %v", q, b.Err())
+		t.FailNow()
+	}
+
+	if expected := []interface{}{1, "%Y", 3, "a", "b"}; !reflect.DeepEqual(params, expected) {
+		t.Logf("expected params %v, got %v", expected, params)
+		t.FailNow()
+	}
+}
+
+func TestRawArgsMismatch(t *testing.T) {
+	b := NewMaryBuilder().Select("id").From("users", "").Where().And(Raw("a = ? AND b = ?", 1))
+
+	if !errors.Is(b.Err(), ErrInvalidExpression) {
+		t.Logf("expected %v, got %v", ErrInvalidExpression, b.Err())
+		t.FailNow()
+	}
+}
+
+func TestRawPostgresHashOperators(t *testing.T) {
+	b := NewBuilder(Postgres{}).Select("id").From("docs", "").Where().And(Raw("data #>> '{a}' = ?", "x"))
+
+	if b.Err() != nil {
+		t.Logf("unexpected error: %v", b.Err())
+		t.FailNow()
+	}
+
+	if q, err := b.Interpolate(); err != nil || q != "SELECT id FROM docs WHERE 1=1  AND data #>> '{a}' = 'x'" {
+		t.Logf("unexpected interpolation: %s %v", q, err)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,103 @@
+package obreron
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"unicode"
+)
+
+// ErrInvalidRecord indica que el valor recibido no puede convertirse en columnas y parámetros
+var ErrInvalidRecord = errors.New("obreron: registro inválido")
+
+// recordPurpose indica para qué se extraen las columnas de un registro
+type recordPurpose int8
+
+const (
+	// forInsert excluye los campos readonly
+	forInsert = recordPurpose(1)
+
+	// forUpdate excluye los campos readonly y pk
+	forUpdate = recordPurpose(2)
+)
+
+// recordValues extrae columnas y parámetros desde v, que puede ser un struct, un puntero a struct o un
+// map con llaves string.
+//
+// Para structs se usan los tags `db` como en Columns, omitiendo los campos marcados `readonly`, los marcados
+// `pk` si es para un UPDATE, y los marcados `omitempty` que tengan su valor cero. Para maps las columnas se
+// ordenan alfabéticamente, de forma que el sql generado sea siempre el mismo, y deben ser nombres simples
+// de columna: letras, dígitos y `_`, sin comenzar con un dígito.
+func recordValues(v interface{}, purpose recordPurpose) ([]string, []interface{}, error) {
+	rv := reflect.ValueOf(v)
+
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, nil, fmt.Errorf("%w: nil", ErrInvalidRecord)
+		}
+		rv = rv.Elem()
+	}
+
+	var cols []string
+	var params []interface{}
+
+	switch {
+	case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+		cols = make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			// las llaves suelen venir de la petición y se escriben en el sql como nombres de columna
+			if !isPlainIdent(k.String()) {
+				return nil, nil, fmt.Errorf("%w: la llave %q no es un nombre de columna válido", ErrInvalidRecord, k.String())
+			}
+			cols = append(cols, k.String())
+		}
+		sort.Strings(cols)
+
+		params = make([]interface{}, len(cols))
+		for i, c := range cols {
+			params[i] = rv.MapIndex(reflect.ValueOf(c).Convert(rv.Type().Key())).Interface()
+		}
+	case rv.Kind() == reflect.Struct && !isScalarStruct(rv.Type()):
+		si := getStructInfo(rv.Type())
+		cols = make([]string, 0, len(si.fields))
+		params = make([]interface{}, 0, len(si.fields))
+
+		for _, f := range si.fields {
+			if f.readonly || (f.pk && purpose == forUpdate) {
+				continue
+			}
+
+			fv, ok := fieldByIndexNoAlloc(rv, f.index)
+			if !ok || (f.omitempty && fv.IsZero()) {
+				continue
+			}
+
+			cols = append(cols, f.column)
+			params = append(params, fv.Interface())
+		}
+	default:
+		return nil, nil, fmt.Errorf("%w: tipo %T no soportado", ErrInvalidRecord, v)
+	}
+
+	if len(cols) == 0 {
+		return nil, nil, fmt.Errorf("%w: %T no tiene columnas", ErrInvalidRecord, v)
+	}
+
+	return cols, params, nil
+}
+
+// isPlainIdent indica si s es un nombre simple de columna: letras, dígitos y `_`, sin comenzar con un dígito
+func isPlainIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,143 @@
+package obreron
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type RecordAudit struct {
+	CreatedAt time.Time `db:"created_at,readonly"`
+	UpdatedBy string    `db:"updated_by,omitempty"`
+}
+
+type recordUser struct {
+	*RecordAudit
+
+	ID     int64  `db:"user_id,pk,omitempty"`
+	Name   string `db:"user_name"`
+	Mail   string `db:"mail,omitempty"`
+	Status int8
+	Secret string `db:"-"`
+}
+
+func TestInsertRecord(t *testing.T) {
+	b := NewMaryInsert("users").
+		Record(recordUser{Name: "mary", Mail: "mary@mail.net", Status: 1}).
+		Record(&recordUser{ID: 0, Name: "ann", Mail: "ann@mail.net"})
+
+	q, p := b.Build()
+	expected := "INSERT INTO users (user_name,mail,status) VALUES (?,?,?),(?,?,?)"
+
+	if b.Err() != nil || q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s %v", q, b.Err())
+		t.FailNow()
+	}
+
+	if !reflect.DeepEqual(p, []interface{}{"mary", "mary@mail.net", int8(1), "ann", "ann@mail.net", int8(0)}) {
+		t.Logf("unexpected params %#v", p)
+		t.FailNow()
+	}
+
+	b.Record(recordUser{Name: "bob"})
+
+	if !errors.Is(b.Err(), ErrInvalidRecord) {
+		t.Logf("expected ErrInvalidRecord for different columns, got %v", b.Err())
+		t.FailNow()
+	}
+}
+
+func TestInsertMapIsDeterministic(t *testing.T) {
+	row := map[string]interface{}{"user_name": "mary", "mail": "mary@mail.net", "status": 1, "deleted_at": nil}
+	expected := "INSERT INTO users (deleted_at,mail,status,user_name) VALUES (?,?,?,?)"
+
+	for i := 0; i < 10; i++ {
+		q, p := NewMaryInsert("users").Record(row).Build()
+
+		if q != expected || !reflect.DeepEqual(p, []interface{}{nil, "mary@mail.net", 1, "mary"}) {
+			t.Logf("expected : %s", expected)
+			t.Logf("generated: %s %v", q, p)
+			t.FailNow()
+		}
+	}
+}
+
+func TestUpdateSetRecord(t *testing.T) {
+	u := recordUser{
+		RecordAudit: &RecordAudit{CreatedAt: time.Now(), UpdatedBy: "root"},
+		ID:          7,
+		Name:        "mary",
+		Status:      2,
+	}
+
+	b := NewMaryUpdate("users").SetRecord(u).SetExpr("version = version + 1").Where().AndParam("user_id", "=", u.ID)
+
+	q, p := b.Build()
+	expected := "UPDATE users SET updated_by = ?, user_name = ?, status = ?, version = version + 1 WHERE 1=1  AND user_id = ?"
+
+	if b.Err() != nil || q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s %v", q, b.Err())
+		t.FailNow()
+	}
+
+	if !reflect.DeepEqual(p, []interface{}{"root", "mary", int8(2), int64(7)}) {
+		t.Logf("unexpected params %#v", p)
+		t.FailNow()
+	}
+}
+
+func TestInvalidRecordIsNotExecuted(t *testing.T) {
+	db, fdb := newFakeDB(t)
+
+	cases := []Builder{
+		NewMaryUpdate("users").SetRecord(10),
+		NewMaryUpdate("users").SetRecord(&recordUser{RecordAudit: &RecordAudit{}, ID: 1}).SetRecord((*recordUser)(nil)),
+		NewMaryInsert("users").Columns("a", "b").Values(1),
+		NewMaryInsert("users").Record(map[string]int{}),
+	}
+
+	for _, b := range cases {
+		if _, err := Exec(context.Background(), db, b); !errors.Is(err, ErrInvalidRecord) {
+			t.Logf("expected ErrInvalidRecord, got %v", err)
+			t.Fail()
+		}
+	}
+
+	if c := fdb.lastCall(); c.query != "" {
+		t.Logf("nothing should be executed, got %s", c.query)
+		t.Fail()
+	}
+}
+
+func TestRecordMapKeysMustBeColumnNames(t *testing.T) {
+	patch := map[string]interface{}{"name": "ana", "role = 'admin', name": "x"}
+
+	for _, b := range []Builder{NewMaryUpdate("users").SetRecord(patch), NewMaryInsert("users").Record(patch)} {
+		if err := b.(interface{ Err() error }).Err(); !errors.Is(err, ErrInvalidRecord) {
+			t.Logf("expected ErrInvalidRecord, got %v", err)
+			t.FailNow()
+		}
+	}
+
+	if err := NewMaryUpdate("users").SetRecord(map[string]int{"_visits2": 1, "año": 2}).Err(); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+}
+
+func TestEmptyStatements(t *testing.T) {
+	for _, b := range []interface{ Err() error }{
+		NewMaryInsert("users"),
+		NewMaryInsert("users").Columns("a"),
+		NewMaryUpdate("users").Where().AndParam("id", "=", 1),
+	} {
+		if err := b.Err(); !errors.Is(err, ErrEmptyStatement) {
+			t.Logf("expected ErrEmptyStatement, got %v", err)
+			t.FailNow()
+		}
+	}
+}
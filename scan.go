@@ -0,0 +1,252 @@
+package obreron
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrScanMapping indica que las columnas de una consulta no calzan con los campos del struct destino
+var ErrScanMapping = errors.New("obreron: columnas y campos no calzan")
+
+// ScanStrictness indica qué hacer con las columnas sin campo y los campos sin columna al escanear structs
+type ScanStrictness int8
+
+const (
+	// ScanLenient descarta las columnas sin campo y deja en su valor cero los campos sin columna
+	ScanLenient = ScanStrictness(0)
+
+	// ScanStrictColumns falla si alguna columna no tiene un campo donde escanearse. Es el valor por defecto
+	ScanStrictColumns = ScanStrictness(1)
+
+	// ScanStrict falla además si algún campo mapeado no recibe una columna
+	ScanStrict = ScanStrictness(2)
+)
+
+// scanConfig contiene las opciones de escaneo
+type scanConfig struct {
+	strictness ScanStrictness
+}
+
+// ScanOption configura el escaneo de filas
+type ScanOption func(c *scanConfig)
+
+// WithStrictness establece cómo tratar las columnas sin campo y los campos sin columna
+func WithStrictness(s ScanStrictness) ScanOption {
+	return func(c *scanConfig) {
+		c.strictness = s
+	}
+}
+
+func newScanConfig(opts []ScanOption) *scanConfig {
+	c := &scanConfig{strictness: ScanStrictColumns}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// ScanAll ejecuta la consulta construida por b con r y escanea todas sus filas en valores de tipo T.
+//
+// Si T es un struct, o un puntero a struct, cada columna se escanea en el campo cuyo tag `db` coincide con
+// su nombre, o cuyo nombre en snake_case coincide si el campo no tiene tag. Los campos con tag `db:"-"` se
+// ignoran y los structs embebidos se aplanan. Los campos puntero reciben nil ante NULL y los campos que
+// implementan sql.Scanner se escanean a través de él. Si T es cualquier otro tipo, la consulta debe
+// devolver una sola columna.
+func ScanAll[T any](ctx context.Context, r Runner, b Builder, opts ...ScanOption) ([]T, error) {
+	var out []T
+
+	_, err := run(ctx, r, b, ModeQuery, func(ctx context.Context, st *Statement) error {
+		if err := queryWith(r)(ctx, st); err != nil {
+			return err
+		}
+
+		var err error
+		out, err = ScanRows[T](st.Rows, opts...)
+		st.Rows = nil
+		st.RowCount = int64(len(out))
+
+		return wrapQueryError(err, st.SQL, st.Params)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// ScanOne ejecuta la consulta construida por b con r y escanea su primera fila en un valor de tipo T.
+// Si la consulta no devuelve filas, el error envuelve a sql.ErrNoRows. Ver ScanAll para el mapeo de columnas
+func ScanOne[T any](ctx context.Context, r Runner, b Builder, opts ...ScanOption) (T, error) {
+	var out T
+
+	_, err := run(ctx, r, b, ModeQuery, func(ctx context.Context, st *Statement) error {
+		if err := queryWith(r)(ctx, st); err != nil {
+			return err
+		}
+
+		rows := st.Rows
+		st.Rows = nil
+
+		var err error
+		out, err = scanFirst[T](rows, opts)
+		if err == nil {
+			st.RowCount = 1
+		} else if errors.Is(err, sql.ErrNoRows) {
+			st.RowCount = 0
+		}
+
+		return wrapQueryError(err, st.SQL, st.Params)
+	})
+
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return out, nil
+}
+
+// scanFirst escanea la primera fila de rows en un valor de tipo T y las cierra.
+// Si no hay filas devuelve sql.ErrNoRows
+func scanFirst[T any](rows *sql.Rows, opts []ScanOption) (T, error) {
+	var zero T
+	defer rows.Close()
+
+	sc, err := newRowScanner[T](rows, newScanConfig(opts))
+	if err != nil {
+		return zero, err
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return zero, err
+		}
+		return zero, sql.ErrNoRows
+	}
+
+	v, err := sc.scan(rows)
+	if err != nil {
+		return zero, err
+	}
+
+	return v, rows.Close()
+}
+
+// ScanRows escanea todas las filas de rows en valores de tipo T y las cierra. Ver ScanAll para el mapeo de columnas
+func ScanRows[T any](rows *sql.Rows, opts ...ScanOption) ([]T, error) {
+	defer rows.Close()
+
+	sc, err := newRowScanner[T](rows, newScanConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	var out []T
+
+	for rows.Next() {
+		v, err := sc.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, rows.Close()
+}
+
+// rowScanner escanea filas de un conjunto de columnas dado en valores de tipo T
+type rowScanner[T any] struct {
+	// indexes tiene la ruta del campo de cada columna, o nil si la columna se descarta.
+	// Es nil si T no es un struct
+	indexes [][]int
+	isPtr   bool
+	dest    []interface{}
+}
+
+// newRowScanner calcula el mapeo entre las columnas de rows y los campos de T
+func newRowScanner[T any](rows *sql.Rows, cfg *scanConfig) (*rowScanner[T], error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	sc := &rowScanner[T]{dest: make([]interface{}, len(columns))}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct {
+		sc.isPtr = true
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || isScalarStruct(t) {
+		if len(columns) != 1 {
+			return nil, fmt.Errorf("%w: %d columnas para el tipo %v", ErrScanMapping, len(columns), t)
+		}
+		return sc, nil
+	}
+
+	si := getStructInfo(t)
+	sc.indexes = make([][]int, len(columns))
+	seen := make(map[string]bool, len(columns))
+
+	for i, c := range columns {
+		fi, ok := si.byColumn[c]
+		if !ok {
+			if cfg.strictness >= ScanStrictColumns {
+				return nil, fmt.Errorf("%w: la columna %q no tiene un campo en %v", ErrScanMapping, c, t)
+			}
+			continue
+		}
+
+		sc.indexes[i] = fi.index
+		seen[c] = true
+	}
+
+	if cfg.strictness >= ScanStrict {
+		for _, fi := range si.fields {
+			if !seen[fi.column] {
+				return nil, fmt.Errorf("%w: el campo para %q no recibe una columna en %v", ErrScanMapping, fi.column, t)
+			}
+		}
+	}
+
+	return sc, nil
+}
+
+// scan escanea la fila actual de rows
+func (sc *rowScanner[T]) scan(rows *sql.Rows) (T, error) {
+	var out T
+
+	if sc.indexes == nil {
+		err := rows.Scan(&out)
+		return out, err
+	}
+
+	var v reflect.Value
+	if sc.isPtr {
+		ptr := reflect.New(reflect.TypeOf(out).Elem())
+		reflect.ValueOf(&out).Elem().Set(ptr)
+		v = ptr.Elem()
+	} else {
+		v = reflect.ValueOf(&out).Elem()
+	}
+
+	for i, index := range sc.indexes {
+		if index == nil {
+			sc.dest[i] = new(interface{})
+			continue
+		}
+		sc.dest[i] = fieldByIndex(v, index).Addr().Interface()
+	}
+
+	err := rows.Scan(sc.dest...)
+	return out, err
+}
@@ -0,0 +1,186 @@
+package obreron
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// upper es un sql.Scanner que guarda el texto recibido en mayúsculas
+type upper string
+
+func (u *upper) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		*u = upper(strings.ToUpper(v))
+	case []byte:
+		*u = upper(strings.ToUpper(string(v)))
+	default:
+		return errors.New("upper: tipo no soportado")
+	}
+	return nil
+}
+
+type audit struct {
+	CreatedAt time.Time
+	UpdatedBy *string `db:"updated_by"`
+}
+
+type Location struct {
+	City string `db:"city"`
+}
+
+type scannedUser struct {
+	audit
+	*Location
+
+	ID       int64   `db:"user_id"`
+	UserName string  // user_name
+	Nick     upper   `db:"nick"`
+	Email    *string `db:"mail"`
+	Secret   string  `db:"-"`
+	internal string
+}
+
+var scannedUserColumns = []string{"user_id", "user_name", "nick", "mail", "created_at", "updated_by", "city"}
+
+func scanUsersQuery(t *testing.T, columns []string, rows [][]driver.Value) (*sql.DB, *Select) {
+	db, fdb := newFakeDB(t)
+
+	b := NewMaryBuilder().Select("*").From("users", "u")
+	q, _ := b.Build()
+
+	fdb.respond(q, fakeResult{columns: columns, rows: rows})
+
+	return db, b
+}
+
+func TestScanAll(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	db, b := scanUsersQuery(t, scannedUserColumns, [][]driver.Value{
+		{int64(1), "mary", "maryo", "mary@mail.net", created, "root", "Santiago"},
+		{int64(2), "ann", "annie", nil, created, nil, "Valparaíso"},
+	})
+
+	users, err := ScanAll[scannedUser](context.Background(), db, b)
+
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	if len(users) != 2 {
+		t.Logf("expected 2 users, got %d", len(users))
+		t.FailNow()
+	}
+
+	mary, ann := users[0], users[1]
+
+	if mary.ID != 1 || mary.UserName != "mary" || mary.Nick != "MARYO" || mary.Email == nil || *mary.Email != "mary@mail.net" {
+		t.Logf("unexpected user %+v", mary)
+		t.Fail()
+	}
+
+	if !mary.CreatedAt.Equal(created) || mary.UpdatedBy == nil || *mary.UpdatedBy != "root" || mary.Location == nil || mary.City != "Santiago" {
+		t.Logf("unexpected embedded fields %+v %+v", mary.audit, mary.Location)
+		t.Fail()
+	}
+
+	if ann.Email != nil || ann.UpdatedBy != nil {
+		t.Logf("NULL should scan into nil pointers %+v", ann)
+		t.Fail()
+	}
+}
+
+func TestScanAllPointersAndScalars(t *testing.T) {
+	db, b := scanUsersQuery(t, []string{"user_id", "user_name"}, [][]driver.Value{
+		{int64(1), "mary"},
+		{int64(2), "ann"},
+	})
+
+	users, err := ScanAll[*scannedUser](context.Background(), db, b)
+
+	if err != nil || len(users) != 2 || users[1].UserName != "ann" {
+		t.Logf("unexpected result %v %v", users, err)
+		t.FailNow()
+	}
+
+	db, b = scanUsersQuery(t, []string{"user_id"}, [][]driver.Value{{int64(7)}, {int64(8)}})
+
+	ids, err := ScanAll[int64](context.Background(), db, b)
+
+	if err != nil || len(ids) != 2 || ids[1] != 8 {
+		t.Logf("unexpected result %v %v", ids, err)
+		t.FailNow()
+	}
+}
+
+func TestScanStrictness(t *testing.T) {
+	db, b := scanUsersQuery(t, []string{"user_id", "password"}, [][]driver.Value{{int64(1), "secret"}})
+
+	_, err := ScanAll[scannedUser](context.Background(), db, b)
+
+	var qe *QueryError
+	if !errors.Is(err, ErrScanMapping) || !errors.As(err, &qe) {
+		t.Logf("expected ErrScanMapping for unknown column, got %v", err)
+		t.Fail()
+	}
+
+	users, err := ScanAll[scannedUser](context.Background(), db, b, WithStrictness(ScanLenient))
+
+	if err != nil || len(users) != 1 || users[0].ID != 1 {
+		t.Logf("lenient scan should ignore unknown columns, got %v %v", users, err)
+		t.Fail()
+	}
+
+	db, b = scanUsersQuery(t, []string{"user_id"}, [][]driver.Value{{int64(1)}})
+
+	if _, err := ScanAll[scannedUser](context.Background(), db, b, WithStrictness(ScanStrict)); !errors.Is(err, ErrScanMapping) {
+		t.Logf("expected ErrScanMapping for missing columns, got %v", err)
+		t.Fail()
+	}
+}
+
+func TestScanOne(t *testing.T) {
+	db, b := scanUsersQuery(t, []string{"user_id", "user_name"}, [][]driver.Value{
+		{int64(1), "mary"},
+		{int64(2), "ann"},
+	})
+
+	u, err := ScanOne[scannedUser](context.Background(), db, b)
+
+	if err != nil || u.UserName != "mary" {
+		t.Logf("unexpected result %v %v", u, err)
+		t.FailNow()
+	}
+
+	db, b = scanUsersQuery(t, []string{"user_id", "user_name"}, nil)
+
+	if _, err := ScanOne[scannedUser](context.Background(), db, b); !errors.Is(err, sql.ErrNoRows) {
+		t.Logf("expected sql.ErrNoRows, got %v", err)
+		t.FailNow()
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"UserName":  "user_name",
+		"ID":        "id",
+		"UserID":    "user_id",
+		"HTTPCode":  "http_code",
+		"Address2":  "address2",
+		"createdAt": "created_at",
+	}
+
+	for in, expected := range cases {
+		if out := snakeCase(in); out != expected {
+			t.Logf("%s: expected %s, generated %s", in, expected, out)
+			t.Fail()
+		}
+	}
+}
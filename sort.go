@@ -0,0 +1,225 @@
+package obreron
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrUnknownSortKey indica que se pidió ordenar por una llave que no está en la lista permitida
+	ErrUnknownSortKey = errors.New("obreron: llave de ordenamiento desconocida")
+
+	// ErrInvalidSort indica que la expresión de ordenamiento está mal formada
+	ErrInvalidSort = errors.New("obreron: ordenamiento inválido")
+)
+
+// NullsOrder indica la posición de los NULL en un ordenamiento
+type NullsOrder int8
+
+const (
+	// NullsDefault deja la posición de los NULL al criterio del motor
+	NullsDefault = NullsOrder(0)
+
+	// NullsFirst ubica los NULL antes que el resto de los valores
+	NullsFirst = NullsOrder(1)
+
+	// NullsLast ubica los NULL después que el resto de los valores
+	NullsLast = NullsOrder(2)
+)
+
+// NullsOrdering es implementada por los dialectos que soportan NULLS FIRST y NULLS LAST de forma nativa.
+// Para los demás dialectos la posición de los NULL se emula con `expr IS NULL`
+type NullsOrdering interface {
+	SupportsNullsOrder() bool
+}
+
+// SortError es el error devuelto al parsear un ordenamiento pedido por el usuario
+type SortError struct {
+	// Term es el término de la entrada que provocó el error
+	Term string
+	// Key es la llave de ordenamiento, si se pudo determinar
+	Key string
+	// Err es ErrUnknownSortKey o ErrInvalidSort
+	Err error
+}
+
+func (e *SortError) Error() string {
+	if e.Key != "" {
+		return e.Err.Error() + ": " + e.Key
+	}
+	return e.Err.Error() + ": " + e.Term
+}
+
+func (e *SortError) Unwrap() error {
+	return e.Err
+}
+
+// SortField es un término de ordenamiento ya validado
+type SortField struct {
+	// Key es la llave pública usada por el usuario
+	Key string
+	// Expr es la expresión sql asociada a la llave
+	Expr  string
+	Desc  bool
+	Nulls NullsOrder
+}
+
+// ParseSort parsea un ordenamiento pedido por el usuario, como `-created_at,name`, validando cada llave
+// contra allowed, que asocia llaves públicas con expresiones sql.
+//
+// Cada término separado por comas tiene la forma `[+|-]llave [ASC|DESC] [NULLS FIRST|NULLS LAST]`,
+// donde el prefijo `-` equivale a DESC. Las palabras clave no distinguen mayúsculas.
+func ParseSort(allowed map[string]string, input string) ([]SortField, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
+	terms := strings.Split(input, ",")
+	fields := make([]SortField, 0, len(terms))
+
+	for _, term := range terms {
+		f, err := parseSortTerm(allowed, term)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+// parseSortTerm parsea un término individual de un ordenamiento
+func parseSortTerm(allowed map[string]string, term string) (SortField, error) {
+	f := SortField{}
+	tokens := strings.Fields(term)
+
+	if len(tokens) == 0 {
+		return f, &SortError{Term: term, Err: ErrInvalidSort}
+	}
+
+	key := tokens[0]
+	prefixed := false
+
+	switch key[0] {
+	case '-':
+		f.Desc = true
+		prefixed = true
+		key = key[1:]
+	case '+':
+		prefixed = true
+		key = key[1:]
+	}
+
+	if key == "" {
+		return f, &SortError{Term: term, Err: ErrInvalidSort}
+	}
+
+	expr, ok := allowed[key]
+	if !ok {
+		return f, &SortError{Term: term, Key: key, Err: ErrUnknownSortKey}
+	}
+
+	f.Key = key
+	f.Expr = expr
+	tokens = tokens[1:]
+
+	if len(tokens) > 0 && !prefixed {
+		switch strings.ToUpper(tokens[0]) {
+		case "ASC":
+			tokens = tokens[1:]
+		case "DESC":
+			f.Desc = true
+			tokens = tokens[1:]
+		}
+	}
+
+	if len(tokens) > 0 {
+		if len(tokens) != 2 || !strings.EqualFold(tokens[0], "NULLS") {
+			return f, &SortError{Term: term, Key: key, Err: ErrInvalidSort}
+		}
+
+		switch strings.ToUpper(tokens[1]) {
+		case "FIRST":
+			f.Nulls = NullsFirst
+		case "LAST":
+			f.Nulls = NullsLast
+		default:
+			return f, &SortError{Term: term, Key: key, Err: ErrInvalidSort}
+		}
+	}
+
+	return f, nil
+}
+
+// SortBy agrega la clausula ORDER BY a partir de un ordenamiento pedido por el usuario, como el valor del
+// parámetro `?sort=` de un endpoint. Solo se aceptan las llaves presentes en allowed, que las asocia con
+// la expresión sql a usar. Ver ParseSort para la sintaxis aceptada.
+//
+// Si input es vacío no se agrega nada. Si el Select ya tiene un ORDER BY los términos se agregan al final.
+func (s *Select) SortBy(allowed map[string]string, input string) (*Select, error) {
+	fields, err := ParseSort(allowed, input)
+	if err != nil {
+		return s, err
+	}
+
+	s.OrderByFields(fields...)
+	return s, nil
+}
+
+// OrderByFields agrega la clausula ORDER BY con los términos fields, emulando NULLS FIRST y NULLS LAST
+// cuando el dialecto no los soporta
+func (s *Select) OrderByFields(fields ...SortField) *Select {
+	if len(fields) == 0 {
+		return s
+	}
+
+	s.touch()
+
+	if s.order.Len() > 0 {
+		s.order.WriteString(", ")
+	} else {
+		s.order.WriteString(" ORDER BY ")
+	}
+
+	native := false
+	if n, ok := s.order.Dialect().(NullsOrdering); ok {
+		native = n.SupportsNullsOrder()
+	}
+
+	for i, f := range fields {
+		if i > 0 {
+			s.order.WriteString(", ")
+		}
+
+		if f.Nulls != NullsDefault && !native {
+			// emula la posición de los NULL ordenando primero por `expr IS NULL`
+			s.order.WriteString(f.Expr)
+			if f.Nulls == NullsFirst {
+				s.order.WriteString(" IS NULL DESC, ")
+			} else {
+				s.order.WriteString(" IS NULL ASC, ")
+			}
+		}
+
+		s.order.WriteString(f.Expr)
+
+		if f.Desc {
+			s.order.WriteString(" DESC")
+		} else {
+			s.order.WriteString(" ASC")
+		}
+
+		if native {
+			switch f.Nulls {
+			case NullsFirst:
+				s.order.WriteString(" NULLS FIRST")
+			case NullsLast:
+				s.order.WriteString(" NULLS LAST")
+			}
+		}
+	}
+
+	s.order.WriteByte(' ')
+
+	return s
+}
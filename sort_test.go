@@ -0,0 +1,104 @@
+package obreron
+
+import (
+	"errors"
+	"testing"
+)
+
+var sortableUsers = map[string]string{
+	"created_at": "u.created_at",
+	"name":       "u.user_name",
+	"status":     "u.user_status",
+}
+
+func TestSortBy(t *testing.T) {
+	b := NewMaryBuilder()
+
+	_, err := b.Select("user_id").From("users", "u").SortBy(sortableUsers, "-created_at,name")
+
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	q := b.String()
+	expected := "SELECT user_id FROM users u  ORDER BY u.created_at DESC, u.user_name ASC "
+
+	if q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+}
+
+func TestSortByNullsEmulated(t *testing.T) {
+	b := NewMaryBuilder()
+
+	_, err := b.Select("user_id").From("users", "u").SortBy(sortableUsers, "name desc nulls last, +status NULLS FIRST")
+
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	q := b.String()
+	expected := "SELECT user_id FROM users u  ORDER BY u.user_name IS NULL ASC, u.user_name DESC, u.user_status IS NULL DESC, u.user_status ASC "
+
+	if q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+}
+
+func TestSortByAppendsToOrderBy(t *testing.T) {
+	b := NewMaryBuilder()
+
+	b.Select("user_id").From("users", "u").OrderBy("u.user_type")
+
+	if _, err := b.SortBy(sortableUsers, "status"); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	q := b.String()
+	expected := "SELECT user_id FROM users u  ORDER BY u.user_type , u.user_status ASC "
+
+	if q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+}
+
+func TestSortByRejects(t *testing.T) {
+	cases := []struct {
+		input string
+		err   error
+		key   string
+	}{
+		{"password", ErrUnknownSortKey, "password"},
+		{"name;DROP TABLE users", ErrUnknownSortKey, "name;DROP"},
+		{"-name desc", ErrInvalidSort, "name"},
+		{"name,,status", ErrInvalidSort, ""},
+		{"name nulls", ErrInvalidSort, "name"},
+		{"name nulls middle", ErrInvalidSort, "name"},
+		{"-", ErrInvalidSort, ""},
+	}
+
+	for _, c := range cases {
+		b := NewMaryBuilder().Select("user_id").From("users", "u")
+		_, err := b.SortBy(sortableUsers, c.input)
+
+		var se *SortError
+		if !errors.As(err, &se) || !errors.Is(err, c.err) || se.Key != c.key {
+			t.Logf("input %q: expected %v with key %q, got %v", c.input, c.err, c.key, err)
+			t.Fail()
+		}
+
+		if q := b.String(); q != "SELECT user_id FROM users u " {
+			t.Logf("input %q: ORDER BY should not be written, generated: %s", c.input, q)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,534 @@
+package obreron
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SpecVersion es la versión del formato de QuerySpec generado por este paquete
+const SpecVersion = 1
+
+// ErrInvalidSpec es devuelto cuando una QuerySpec no está bien formada
+var ErrInvalidSpec = errors.New("obreron: especificación de consulta inválida")
+
+// QuerySpec es la representación serializable de un Select. Cada clausula guarda el fragmento sql
+// tal como lo escribió el builder, junto con sus parámetros tipados, de forma que al reconstruir
+// el Select se obtienen exactamente el mismo sql y los mismos parámetros.
+//
+// Los fragmentos se ejecutan tal cual, por lo que una QuerySpec equivale a sql arbitrario: Validate
+// rechaza las especificaciones mal formadas, pero solo reconstruya especificaciones de origen confiable,
+// como las generadas por su propia aplicación y firmadas o guardadas en el servidor
+type QuerySpec struct {
+	Version int        `json:"version"`
+	Dialect string     `json:"dialect"`
+	Columns ClauseSpec `json:"columns"`
+	Source  ClauseSpec `json:"source"`
+	Joins   ClauseSpec `json:"joins"`
+	Where   ClauseSpec `json:"where"`
+	Group   ClauseSpec `json:"group"`
+	Having  ClauseSpec `json:"having"`
+	Order   ClauseSpec `json:"order"`
+	Window  ClauseSpec `json:"window"`
+	Limit   int64      `json:"limit"`
+	Offset  int64      `json:"offset"`
+}
+
+// ClauseSpec es el fragmento sql de una clausula con sus parámetros
+type ClauseSpec struct {
+	SQL    string      `json:"sql"`
+	Params []ParamSpec `json:"params,omitempty"`
+}
+
+// ParamSpec es un parámetro junto con su tipo, para poder recuperarlo sin pérdida desde json
+type ParamSpec struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Spec devuelve la representación serializable del Select.
+// Falla si el Select tiene un error de construcción, si usa opciones que la especificación no guarda,
+// como pistas, modificadores, bloqueos o comentarios, o si algún parámetro es de un tipo que no puede
+// serializarse sin pérdida.
+func (s *Select) Spec() (*QuerySpec, error) {
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	if opt := s.unserializable(); opt != "" {
+		return nil, fmt.Errorf("%w: %s no puede serializarse", ErrInvalidSpec, opt)
+	}
+
+	name := DialectName(s.SQLBuilder.dialect)
+	if name == "" {
+		return nil, fmt.Errorf("%w: dialecto %T no registrado", ErrInvalidSpec, s.SQLBuilder.dialect)
+	}
+
+	spec := &QuerySpec{
+		Version: SpecVersion,
+		Dialect: name,
+		Limit:   s.limit,
+		Offset:  s.offset,
+	}
+
+	clauses := []struct {
+		dst *ClauseSpec
+		src *SQLBuilder
+	}{
+		{&spec.Columns, s.columns},
+		{&spec.Source, s.source},
+		{&spec.Joins, s.joins},
+		{&spec.Where, s.filter},
+		{&spec.Group, s.group},
+		{&spec.Having, s.having},
+		{&spec.Order, s.order},
+		{&spec.Window, s.window},
+	}
+
+	for _, c := range clauses {
+		c.dst.SQL = c.src.String()
+
+		for _, p := range c.src.params {
+			ps, err := encodeParam(p)
+			if err != nil {
+				return nil, err
+			}
+			c.dst.Params = append(c.dst.Params, ps)
+		}
+	}
+
+	// las columnas se escriben seguidas de una coma que String() descarta
+	spec.Columns.SQL = strings.TrimSuffix(spec.Columns.SQL, ",")
+
+	return spec, nil
+}
+
+// unserializable devuelve el nombre de la primera opción del Select que QuerySpec no guarda, o un string vacío
+func (s *Select) unserializable() string {
+	switch {
+	case len(s.optimizerHints) > 0:
+		return "las pistas del optimizador"
+	case s.modifiers != 0 || len(s.distinctOn) > 0:
+		return "los modificadores de SELECT"
+	case len(s.locks) > 0:
+		return "los bloqueos"
+	case len(s.comments) > 0 || len(s.tags) > 0:
+		return "los comentarios y etiquetas"
+	}
+	return ""
+}
+
+// Validate verifica que la especificación esté bien formada: versión y dialecto conocidos, clausulas
+// que comiencen con su palabra clave, tantas marcas de parámetro como parámetros y parámetros decodificables.
+// Además cada clausula debe ser un fragmento cerrado: sin `;`, comentarios, literales sin cerrar ni paréntesis
+// desbalanceados, y sin palabras clave de otras clausulas fuera de los paréntesis
+func (spec *QuerySpec) Validate() error {
+	if spec.Version != SpecVersion {
+		return fmt.Errorf("%w: versión %d no soportada", ErrInvalidSpec, spec.Version)
+	}
+
+	d, ok := LookupDialect(spec.Dialect)
+	if !ok {
+		return fmt.Errorf("%w: dialecto %q desconocido", ErrInvalidSpec, spec.Dialect)
+	}
+
+	if spec.Limit < -1 || spec.Offset < -1 {
+		return fmt.Errorf("%w: limit y offset deben ser mayores o iguales a -1", ErrInvalidSpec)
+	}
+
+	clauses := []struct {
+		name     string
+		c        *ClauseSpec
+		prefixes []string
+		keywords []string
+	}{
+		{"columns", &spec.Columns, nil, nil},
+		{"source", &spec.Source, []string{" FROM "}, []string{"FROM"}},
+		{"joins", &spec.Joins, []string{" INNER JOIN ", " LEFT JOIN ", " RIGHT JOIN ", " STRAIGHT_JOIN "}, []string{"INNER", "LEFT", "RIGHT", "JOIN", "STRAIGHT_JOIN"}},
+		{"where", &spec.Where, []string{" WHERE "}, []string{"WHERE"}},
+		{"group", &spec.Group, []string{" GROUP BY ", " HAVING "}, []string{"GROUP", "HAVING"}},
+		{"having", &spec.Having, []string{" HAVING "}, []string{"HAVING"}},
+		{"order", &spec.Order, []string{" ORDER BY "}, []string{"ORDER"}},
+		{"window", &spec.Window, []string{" WINDOW "}, []string{"WINDOW"}},
+	}
+
+	mysql := mysqlLexing(d)
+
+	for _, cl := range clauses {
+		if cl.c.SQL == "" {
+			if len(cl.c.Params) > 0 {
+				return fmt.Errorf("%w: %s tiene parámetros pero no sql", ErrInvalidSpec, cl.name)
+			}
+			continue
+		}
+
+		if strings.IndexByte(cl.c.SQL, 0) > -1 {
+			return fmt.Errorf("%w: %s contiene NUL", ErrInvalidSpec, cl.name)
+		}
+
+		if cl.prefixes != nil && !hasAnyPrefix(cl.c.SQL, cl.prefixes) {
+			return fmt.Errorf("%w: %s debe comenzar con %q", ErrInvalidSpec, cl.name, strings.TrimSpace(cl.prefixes[0]))
+		}
+
+		if err := validateFragment(cl.c.SQL, cl.keywords, mysql); err != nil {
+			return fmt.Errorf("%w: %s %s", ErrInvalidSpec, cl.name, err)
+		}
+
+		if n := len(placeholders(cl.c.SQL, mysql)); n != len(cl.c.Params) {
+			return fmt.Errorf("%w: %s tiene %d marcas de parámetro y %d parámetros", ErrInvalidSpec, cl.name, n, len(cl.c.Params))
+		}
+
+		for i, p := range cl.c.Params {
+			if _, err := decodeParam(p); err != nil {
+				return fmt.Errorf("%s parámetro %d: %w", cl.name, i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// NewSelectFromSpec reconstruye un Select a partir de su especificación, validándola antes
+func NewSelectFromSpec(spec *QuerySpec) (*Select, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	d, _ := LookupDialect(spec.Dialect)
+	s := NewBuilder(d)
+
+	if err := s.loadSpec(spec); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// loadSpec carga en el Select las clausulas de una especificación ya validada
+func (s *Select) loadSpec(spec *QuerySpec) error {
+	clauses := []struct {
+		src *ClauseSpec
+		dst *SQLBuilder
+	}{
+		{&spec.Columns, s.columns},
+		{&spec.Source, s.source},
+		{&spec.Joins, s.joins},
+		{&spec.Where, s.filter},
+		{&spec.Group, s.group},
+		{&spec.Having, s.having},
+		{&spec.Order, s.order},
+		{&spec.Window, s.window},
+	}
+
+	for _, c := range clauses {
+		c.dst.WriteString(c.src.SQL)
+
+		for _, ps := range c.src.Params {
+			p, err := decodeParam(ps)
+			if err != nil {
+				return err
+			}
+			c.dst.AddParam(p)
+		}
+	}
+
+	if s.columns.Len() > 0 {
+		s.columns.WriteByte(44)
+	}
+
+	s.limit = spec.Limit
+	s.offset = spec.Offset
+
+	return nil
+}
+
+// MarshalJSON implementa json.Marshaler serializando la especificación del Select
+func (s *Select) MarshalJSON() ([]byte, error) {
+	spec, err := s.Spec()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(spec)
+}
+
+// UnmarshalJSON implementa json.Unmarshaler reconstruyendo el Select desde su especificación.
+// El Select recibido es reemplazado por completo, incluido su dialecto
+func (s *Select) UnmarshalJSON(data []byte) error {
+	spec := &QuerySpec{}
+
+	if err := json.Unmarshal(data, spec); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSpec, err)
+	}
+
+	ns, err := NewSelectFromSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	*s = *ns
+	return nil
+}
+
+// validateFragment verifica que el fragmento sql de una clausula no pueda alterar el resto de la consulta.
+// keywords son las palabras clave de la propia clausula, las únicas permitidas fuera de los paréntesis
+func validateFragment(sql string, keywords []string, mysql bool) error {
+	toks := tokenize(sql, mysql)
+	depth := 0
+
+	for i, t := range toks {
+		switch t.kind {
+		case tokComment, tokLineComment:
+			return errors.New("contiene un comentario")
+		case tokQuoted:
+			// un literal sin cerrar se extendería sobre las clausulas siguientes
+			if skipQuoted(t.text+" ", 0, mysql) > len(t.text) {
+				return errors.New("contiene un literal sin cerrar")
+			}
+		case tokOpen:
+			depth++
+		case tokClose:
+			if depth--; depth < 0 {
+				return errors.New("tiene paréntesis desbalanceados")
+			}
+		case tokOther:
+			if strings.IndexByte(t.text, ';') > -1 {
+				return errors.New("contiene `;`")
+			}
+		case tokWord:
+			w := strings.ToUpper(t.text)
+			if depth > 0 || !specForbidden[w] || (specFunctions[w] && isCall(toks, i)) || isKeyword(w, keywords) || indexHintWord(toks, i, w) {
+				continue
+			}
+			return fmt.Errorf("contiene %s fuera de paréntesis", w)
+		}
+	}
+
+	if depth != 0 {
+		return errors.New("tiene paréntesis desbalanceados")
+	}
+
+	return nil
+}
+
+// specForbidden son las palabras clave que inician otra clausula o sentencia, y que un fragmento solo puede
+// contener dentro de paréntesis, como en una subconsulta
+var specForbidden = func() map[string]bool {
+	m := map[string]bool{"INTO": true}
+	for w := range clauseWords {
+		m[w] = true
+	}
+	return m
+}()
+
+// specFunctions son las palabras de specForbidden que también son nombres de funciones, como `LEFT(name, 3)`,
+// y que por eso se permiten seguidas de un paréntesis
+var specFunctions = map[string]bool{"LEFT": true, "RIGHT": true}
+
+// isKeyword indica si w es una de las palabras keywords
+func isKeyword(w string, keywords []string) bool {
+	for _, k := range keywords {
+		if w == k {
+			return true
+		}
+	}
+	return false
+}
+
+// indexHintWord indica si la palabra w de toks[i] es parte de una pista de índice, como `USE INDEX FOR ORDER BY`
+func indexHintWord(toks []sqlToken, i int, w string) bool {
+	if i == 0 || toks[i-1].kind != tokWord {
+		return false
+	}
+
+	prev := strings.ToUpper(toks[i-1].text)
+
+	switch w {
+	case "FOR":
+		return prev == "INDEX" || prev == "KEY"
+	case "JOIN", "ORDER", "GROUP":
+		return prev == "FOR"
+	}
+
+	return false
+}
+
+// hasAnyPrefix indica si s comienza con alguno de los prefijos prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeParam serializa un parámetro junto con su tipo
+func encodeParam(p interface{}) (ParamSpec, error) {
+	var t string
+	var v interface{} = p
+
+	switch tp := p.(type) {
+	case nil:
+		return ParamSpec{Type: "null"}, nil
+	case bool:
+		t = "bool"
+	case string:
+		t = "string"
+	case int:
+		t = "int"
+	case int8:
+		t = "int8"
+	case int16:
+		t = "int16"
+	case int32:
+		t = "int32"
+	case int64:
+		t = "int64"
+	case uint:
+		t = "uint"
+	case uint8:
+		t = "uint8"
+	case uint16:
+		t = "uint16"
+	case uint32:
+		t = "uint32"
+	case uint64:
+		t = "uint64"
+	case float32:
+		t = "float32"
+	case float64:
+		t = "float64"
+	case []byte:
+		t, v = "bytes", base64.StdEncoding.EncodeToString(tp)
+	case time.Time:
+		t, v = "time", tp.Format(time.RFC3339Nano)
+	default:
+		return ParamSpec{}, fmt.Errorf("%w: parámetro de tipo %T no soportado", ErrInvalidSpec, p)
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return ParamSpec{}, fmt.Errorf("%w: %v", ErrInvalidSpec, err)
+	}
+
+	return ParamSpec{Type: t, Value: raw}, nil
+}
+
+// decodeParam recupera un parámetro serializado con encodeParam
+func decodeParam(ps ParamSpec) (interface{}, error) {
+	if ps.Type == "null" {
+		return nil, nil
+	}
+
+	invalid := fmt.Errorf("%w: valor %s inválido para el tipo %q", ErrInvalidSpec, ps.Value, ps.Type)
+
+	var str string
+	var num json.Number
+	var b bool
+
+	switch ps.Type {
+	case "bool":
+		if json.Unmarshal(ps.Value, &b) != nil {
+			return nil, invalid
+		}
+		return b, nil
+	case "string", "bytes", "time":
+		if json.Unmarshal(ps.Value, &str) != nil {
+			return nil, invalid
+		}
+	default:
+		d := json.NewDecoder(bytes.NewReader(ps.Value))
+		d.UseNumber()
+		if d.Decode(&num) != nil {
+			return nil, invalid
+		}
+	}
+
+	switch ps.Type {
+	case "string":
+		return str, nil
+	case "bytes":
+		v, err := base64.StdEncoding.DecodeString(str)
+		if err != nil {
+			return nil, invalid
+		}
+		return v, nil
+	case "time":
+		v, err := time.Parse(time.RFC3339Nano, str)
+		if err != nil {
+			return nil, invalid
+		}
+		return v, nil
+	case "float32", "float64":
+		bits := 64
+		if ps.Type == "float32" {
+			bits = 32
+		}
+		v, err := strconv.ParseFloat(num.String(), bits)
+		if err != nil {
+			return nil, invalid
+		}
+		if bits == 32 {
+			return float32(v), nil
+		}
+		return v, nil
+	case "int", "int8", "int16", "int32", "int64":
+		return decodeInt(ps.Type, num.String(), invalid)
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		return decodeUint(ps.Type, num.String(), invalid)
+	}
+
+	return nil, fmt.Errorf("%w: tipo de parámetro %q desconocido", ErrInvalidSpec, ps.Type)
+}
+
+// decodeInt convierte n al tipo entero con signo t
+func decodeInt(t string, n string, invalid error) (interface{}, error) {
+	bits := map[string]int{"int": strconv.IntSize, "int8": 8, "int16": 16, "int32": 32, "int64": 64}[t]
+
+	v, err := strconv.ParseInt(n, 10, bits)
+	if err != nil {
+		return nil, invalid
+	}
+
+	switch t {
+	case "int":
+		return int(v), nil
+	case "int8":
+		return int8(v), nil
+	case "int16":
+		return int16(v), nil
+	case "int32":
+		return int32(v), nil
+	}
+
+	return v, nil
+}
+
+// decodeUint convierte n al tipo entero sin signo t
+func decodeUint(t string, n string, invalid error) (interface{}, error) {
+	bits := map[string]int{"uint": strconv.IntSize, "uint8": 8, "uint16": 16, "uint32": 32, "uint64": 64}[t]
+
+	v, err := strconv.ParseUint(n, 10, bits)
+	if err != nil {
+		return nil, invalid
+	}
+
+	switch t {
+	case "uint":
+		return uint(v), nil
+	case "uint8":
+		return uint8(v), nil
+	case "uint16":
+		return uint16(v), nil
+	case "uint32":
+		return uint32(v), nil
+	}
+
+	return v, nil
+}
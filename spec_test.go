@@ -0,0 +1,153 @@
+package obreron
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSpecRoundTrip(t *testing.T) {
+	// heavyQueryBuild deja en b la subconsulta con joins, agrupación y parámetros
+	b := NewMaryBuilder()
+	heavyQueryBuild(t, b)
+
+	outer := NewMaryBuilder()
+	outer.Select("id", "name").From(b, "out_detail").Where().
+		AndParam("created_at", ">=", time.Date(2024, 1, 1, 10, 0, 0, 5, time.UTC)).
+		AndParam("hash", "=", []byte{0xde, 0xad}).
+		AndParam("deleted_at", "IS", nil).
+		AndParam("ratio", ">", float32(0.5)).
+		AndParam("code", "=", "it's ? a string").
+		AndParam("active", "=", true).
+		AndParam("big", "<", uint64(1<<63)).
+		OrderBy("id DESC").Limit(10).Offset(20)
+
+	data, err := json.Marshal(outer)
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	decoded := &Select{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Logf("unexpected error: %v\n%s", err, data)
+		t.FailNow()
+	}
+
+	eq, ep := outer.Build()
+	dq, dp := decoded.Build()
+
+	if eq != dq {
+		t.Logf("expected : %s", eq)
+		t.Logf("generated: %s", dq)
+		t.FailNow()
+	}
+
+	if !reflect.DeepEqual(ep, dp) {
+		t.Logf("expected : %#v", ep)
+		t.Logf("generated: %#v", dp)
+		t.FailNow()
+	}
+}
+
+func TestSpecUnsupportedParam(t *testing.T) {
+	b := NewMaryBuilder().Select("id").From("users", "u").Where().AndParam("id", "=", struct{}{})
+
+	if _, err := b.Spec(); !errors.Is(err, ErrInvalidSpec) {
+		t.Logf("expected ErrInvalidSpec, got %v", err)
+		t.FailNow()
+	}
+}
+
+func TestSpecValidate(t *testing.T) {
+	valid := func() *QuerySpec {
+		spec, err := NewMaryBuilder().Select("id").From("users", "u").Where().AndParam("id", "=", 1).Spec()
+		if err != nil {
+			t.Logf("unexpected error: %v", err)
+			t.FailNow()
+		}
+		return spec
+	}
+
+	if err := valid().Validate(); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	cases := map[string]func(s *QuerySpec){
+		"version":       func(s *QuerySpec) { s.Version = 99 },
+		"dialect":       func(s *QuerySpec) { s.Dialect = "oracle" },
+		"limit":         func(s *QuerySpec) { s.Limit = -5 },
+		"source prefix": func(s *QuerySpec) { s.Source.SQL = "users; DROP TABLE users" },
+		"where prefix":  func(s *QuerySpec) { s.Where.SQL = " AND 1=1" },
+		"marks":         func(s *QuerySpec) { s.Where.Params = nil },
+		"extra params":  func(s *QuerySpec) { s.Where.SQL = " WHERE 1=1 AND 'id = ?'" },
+		"param type":    func(s *QuerySpec) { s.Where.Params[0].Type = "complex128" },
+		"param value":   func(s *QuerySpec) { s.Where.Params[0].Value = json.RawMessage(`"uno"`) },
+		"int overflow":  func(s *QuerySpec) { s.Where.Params[0] = ParamSpec{Type: "int8", Value: json.RawMessage(`300`)} },
+		"params no sql": func(s *QuerySpec) { s.Order.Params = []ParamSpec{{Type: "null"}} },
+		"stacked":       func(s *QuerySpec) { s.Source.SQL = " FROM users; DROP TABLE x" },
+		"union":         func(s *QuerySpec) { s.Where.SQL = " WHERE id = ? UNION SELECT password FROM users" },
+		"comment":       func(s *QuerySpec) { s.Where.SQL = " WHERE id = ? -- " },
+		"open quote":    func(s *QuerySpec) { s.Where.SQL = " WHERE id = ? AND name = 'x" },
+		"parens":        func(s *QuerySpec) { s.Where.SQL = " WHERE (id = ?" },
+		"lock":          func(s *QuerySpec) { s.Order.SQL = " ORDER BY id FOR UPDATE" },
+		"union call":    func(s *QuerySpec) { s.Where.SQL = " WHERE 1=1 AND id = ? UNION(SELECT password FROM users)" },
+		"limit call":    func(s *QuerySpec) { s.Order.SQL = " ORDER BY id LIMIT(1)" },
+	}
+
+	for name, mutate := range cases {
+		spec := valid()
+		mutate(spec)
+
+		if _, err := NewSelectFromSpec(spec); !errors.Is(err, ErrInvalidSpec) {
+			t.Logf("%s: expected ErrInvalidSpec, got %v", name, err)
+			t.Fail()
+		}
+	}
+}
+
+func TestSpecRoundTripOptions(t *testing.T) {
+	b := NewMaryBuilder().Select("id", Raw("LENGTH(name) > ?", 3)).
+		AddColumn(OverWindow("ROW_NUMBER()", "w"), "rn").
+		From("users", "u").UseIndex("idx_name").
+		Straight("roles", "r", "r.id = u.role_id").IndexHint(IndexHint{Kind: ForceIndexHint, For: "JOIN", Indexes: []string{"PRIMARY"}}).
+		Where().AndParam("LEFT(u.name, 1)", "=", "a").
+		Window("w", NewWindow().PartitionBy("u.role_id").OrderBy("u.id")).
+		OrderBy("id")
+
+	spec, err := b.Spec()
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	decoded, err := NewSelectFromSpec(spec)
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	if eq, dq := b.String(), decoded.String(); eq != dq {
+		t.Logf("expected : %s", eq)
+		t.Logf("generated: %s", dq)
+		t.FailNow()
+	}
+
+	cases := map[string]*Select{
+		"distinct": NewMaryBuilder().Distinct().Select("id").From("users", ""),
+		"hint":     NewMaryBuilder().Select("id").From("users", "").MaxExecutionTime(time.Second),
+		"lock":     NewMaryBuilder().Select("id").From("users", "").ForUpdate(),
+		"comment":  NewMaryBuilder().Select("id").From("users", "").Comment("c"),
+		"err":      NewMaryBuilder().Select("id").From("users", "").Where().And(Raw("id = ?")),
+	}
+
+	for name, b := range cases {
+		if _, err := b.Spec(); err == nil {
+			t.Logf("%s: expected an error", name)
+			t.Fail()
+		}
+	}
+}
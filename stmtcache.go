@@ -0,0 +1,242 @@
+package obreron
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+)
+
+var (
+	_ Runner = (*StmtCache)(nil)
+
+	_ Preparer = (*sql.DB)(nil)
+	_ Preparer = (*sql.Tx)(nil)
+	_ Preparer = (*sql.Conn)(nil)
+)
+
+// DefaultStmtCacheSize es la cantidad de sentencias que guarda un StmtCache creado con tamaño menor a 1
+const DefaultStmtCacheSize = 100
+
+// ErrStmtCacheClosed es devuelto al usar un StmtCache ya cerrado
+var ErrStmtCacheClosed = errors.New("obreron: caché de sentencias cerrado")
+
+// Preparer es un Runner capaz de preparar sentencias. Es satisfecho por *sql.DB, *sql.Tx y *sql.Conn
+type Preparer interface {
+	Runner
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// StmtCacheStats son las métricas de un StmtCache
+type StmtCacheStats struct {
+	// Hits es la cantidad de ejecuciones que reutilizaron una sentencia preparada
+	Hits int64
+	// Misses es la cantidad de ejecuciones que tuvieron que preparar la sentencia
+	Misses int64
+	// Evictions es la cantidad de sentencias descartadas por falta de espacio
+	Evictions int64
+	// Size es la cantidad de sentencias guardadas
+	Size int
+}
+
+// stmtEntry es una sentencia guardada en el caché
+type stmtEntry struct {
+	key  string
+	stmt *sql.Stmt
+	// refs es la cantidad de ejecuciones en curso que usan la sentencia
+	refs int
+	// evicted indica que la sentencia debe cerrarse cuando refs llegue a cero
+	evicted bool
+}
+
+// StmtCache es un caché LRU de sentencias preparadas, usando el sql de la consulta como llave.
+// Implementa Runner, por lo que puede usarse en lugar de un *sql.DB o *sql.Tx para ejecutar builders:
+//
+//	cache := obreron.NewStmtCache(db, 200)
+//	defer cache.Close()
+//	rows, err := b.Query(ctx, cache)
+//
+// Las sentencias quedan asociadas al Preparer con que se creó el caché; si es un *sql.Tx, el caché
+// no debe usarse después de terminar la transacción. Es seguro para uso concurrente
+type StmtCache struct {
+	p    Preparer
+	size int
+
+	mu     sync.Mutex
+	lru    *list.List
+	items  map[string]*list.Element
+	stats  StmtCacheStats
+	closed bool
+}
+
+// NewStmtCache devuelve un caché de hasta size sentencias preparadas con p
+func NewStmtCache(p Preparer, size int) *StmtCache {
+	if size < 1 {
+		size = DefaultStmtCacheSize
+	}
+
+	return &StmtCache{
+		p:     p,
+		size:  size,
+		lru:   list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+// acquire devuelve la sentencia preparada para q, preparándola si no está en el caché.
+// Debe liberarse con release después de usarla
+func (c *StmtCache) acquire(ctx context.Context, q string) (*stmtEntry, error) {
+	c.mu.Lock()
+
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrStmtCacheClosed
+	}
+
+	if el, ok := c.items[q]; ok {
+		c.lru.MoveToFront(el)
+		e := el.Value.(*stmtEntry)
+		e.refs++
+		c.stats.Hits++
+		c.mu.Unlock()
+		return e, nil
+	}
+
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	stmt, err := c.p.PrepareContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		_ = stmt.Close()
+		return nil, ErrStmtCacheClosed
+	}
+
+	// otra ejecución pudo preparar la misma sentencia mientras tanto
+	if el, ok := c.items[q]; ok {
+		_ = stmt.Close()
+		c.lru.MoveToFront(el)
+		e := el.Value.(*stmtEntry)
+		e.refs++
+		return e, nil
+	}
+
+	// el sql puede apuntar a la memoria de un builder que será reutilizada, así que la llave se copia
+	e := &stmtEntry{key: strings.Clone(q), stmt: stmt, refs: 1}
+	c.items[e.key] = c.lru.PushFront(e)
+
+	for c.lru.Len() > c.size {
+		c.evict(c.lru.Back())
+		c.stats.Evictions++
+	}
+
+	return e, nil
+}
+
+// evict saca del caché la sentencia de el, cerrándola si no está en uso. Debe llamarse con mu tomado
+func (c *StmtCache) evict(el *list.Element) {
+	e := c.lru.Remove(el).(*stmtEntry)
+	delete(c.items, e.key)
+
+	e.evicted = true
+	if e.refs == 0 {
+		_ = e.stmt.Close()
+	}
+}
+
+// release libera una sentencia obtenida con acquire, cerrándola si fue descartada mientras se usaba
+func (c *StmtCache) release(e *stmtEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e.refs--
+	if e.evicted && e.refs == 0 {
+		_ = e.stmt.Close()
+	}
+}
+
+// QueryContext ejecuta la consulta q con la sentencia preparada del caché
+func (c *StmtCache) QueryContext(ctx context.Context, q string, args ...interface{}) (*sql.Rows, error) {
+	e, err := c.acquire(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer c.release(e)
+
+	// database/sql posterga el cierre de la sentencia hasta que se cierren las filas
+	return e.stmt.QueryContext(ctx, args...)
+}
+
+// QueryRowContext ejecuta la consulta q con la sentencia preparada del caché, esperando a lo más una fila.
+// Si la sentencia no pudo prepararse, la consulta se ejecuta directamente para entregar el error en la fila.
+// Si el caché está cerrado la consulta no se ejecuta y la fila devuelve context.Canceled, ya que *sql.Row
+// no puede llevar otro error; QueryRow de este paquete devuelve en cambio ErrStmtCacheClosed
+func (c *StmtCache) QueryRowContext(ctx context.Context, q string, args ...interface{}) *sql.Row {
+	row, err := c.queryRow(ctx, q, args...)
+	if err != nil {
+		// con el contexto cancelado database/sql no llega a ejecutar la consulta
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		return c.p.QueryRowContext(canceled, q, args...)
+	}
+
+	return row
+}
+
+// queryRow es como QueryRowContext, pero devuelve ErrStmtCacheClosed si el caché está cerrado
+func (c *StmtCache) queryRow(ctx context.Context, q string, args ...interface{}) (*sql.Row, error) {
+	e, err := c.acquire(ctx, q)
+	if errors.Is(err, ErrStmtCacheClosed) {
+		return nil, err
+	}
+
+	if err != nil {
+		return c.p.QueryRowContext(ctx, q, args...), nil
+	}
+	defer c.release(e)
+
+	return e.stmt.QueryRowContext(ctx, args...), nil
+}
+
+// ExecContext ejecuta la sentencia q con la sentencia preparada del caché
+func (c *StmtCache) ExecContext(ctx context.Context, q string, args ...interface{}) (sql.Result, error) {
+	e, err := c.acquire(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer c.release(e)
+
+	return e.stmt.ExecContext(ctx, args...)
+}
+
+// Stats devuelve las métricas del caché
+func (c *StmtCache) Stats() StmtCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.stats
+	s.Size = c.lru.Len()
+	return s
+}
+
+// Close cierra todas las sentencias del caché. Las que estén en uso se cierran al terminar su ejecución
+func (c *StmtCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+
+	for c.lru.Len() > 0 {
+		c.evict(c.lru.Back())
+	}
+
+	return nil
+}
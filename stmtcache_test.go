@@ -0,0 +1,161 @@
+package obreron
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestStmtCacheReusesStatements(t *testing.T) {
+	db, fdb := newFakeDB(t)
+	db.SetMaxOpenConns(1)
+
+	cache := NewStmtCache(db, 2)
+	defer cache.Close()
+
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		b := NewMaryBuilder().Select("user_id").From("users", "u").Where().AndParam("user_id", "=", i)
+		if _, err := b.Exec(ctx, cache); err != nil {
+			t.Logf("unexpected error: %v", err)
+			t.FailNow()
+		}
+	}
+
+	if s := cache.Stats(); s.Hits != 4 || s.Misses != 1 || s.Evictions != 0 || s.Size != 1 {
+		t.Logf("unexpected stats %+v", s)
+		t.FailNow()
+	}
+
+	if fdb.prepares != 1 {
+		t.Logf("expected 1 prepare, got %d", fdb.prepares)
+		t.FailNow()
+	}
+
+	if c := fdb.lastCall(); len(c.args) != 1 || c.args[0] != int64(4) {
+		t.Logf("unexpected call %#v", c)
+		t.FailNow()
+	}
+}
+
+func TestStmtCacheEvictsAndCloses(t *testing.T) {
+	db, fdb := newFakeDB(t)
+	db.SetMaxOpenConns(1)
+
+	cache := NewStmtCache(db, 2)
+	ctx := context.Background()
+
+	tables := []string{"a", "b", "c", "a"}
+	for _, table := range tables {
+		if _, err := NewMaryBuilder().Select("id").From(table, "").Exec(ctx, cache); err != nil {
+			t.Logf("unexpected error: %v", err)
+			t.FailNow()
+		}
+	}
+
+	// a se descarta al preparar c, y b al volver a preparar a
+	if s := cache.Stats(); s.Hits != 0 || s.Misses != 4 || s.Evictions != 2 || s.Size != 2 {
+		t.Logf("unexpected stats %+v", s)
+		t.FailNow()
+	}
+
+	if fdb.closes != 2 {
+		t.Logf("expected 2 closed statements, got %d", fdb.closes)
+		t.FailNow()
+	}
+
+	_ = cache.Close()
+
+	if fdb.closes != 4 {
+		t.Logf("expected 4 closed statements, got %d", fdb.closes)
+		t.FailNow()
+	}
+
+	if _, err := NewMaryBuilder().Select("id").From("a", "").Exec(ctx, cache); !errors.Is(err, ErrStmtCacheClosed) {
+		t.Logf("expected ErrStmtCacheClosed, got %v", err)
+		t.FailNow()
+	}
+
+	calls := len(fdb.calls)
+
+	for _, r := range []Runner{cache, WithDialect(cache, Mysql{})} {
+		var id int
+		if err := NewMaryBuilder().Select("id").From("d", "").QueryRow(ctx, r).Scan(&id); !errors.Is(err, ErrStmtCacheClosed) {
+			t.Logf("expected ErrStmtCacheClosed, got %v", err)
+			t.FailNow()
+		}
+	}
+
+	if err := cache.QueryRowContext(ctx, "SELECT id FROM d").Err(); !errors.Is(err, context.Canceled) {
+		t.Logf("expected context.Canceled, got %v", err)
+		t.FailNow()
+	}
+
+	if len(fdb.calls) != calls {
+		t.Logf("nothing should be executed on a closed cache, got %+v", fdb.calls[calls:])
+		t.FailNow()
+	}
+}
+
+func TestStmtCacheKeepsRowsOpenAfterEviction(t *testing.T) {
+	db, fdb := newFakeDB(t)
+
+	cache := NewStmtCache(db, 1)
+	defer cache.Close()
+
+	ctx := context.Background()
+	first := NewMaryBuilder().Select("id").From("a", "")
+	q, _ := first.Build()
+	fdb.respond(q, fakeResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}, {int64(2)}}})
+
+	rows, err := first.Query(ctx, cache)
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	// descarta la sentencia de first mientras sus filas siguen abiertas
+	if _, err := NewMaryBuilder().Select("id").From("b", "").Exec(ctx, cache); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	n := 0
+	for rows.Next() {
+		n++
+	}
+
+	if err := rows.Close(); err != nil || n != 2 {
+		t.Logf("expected 2 rows, got %d %v", n, err)
+		t.FailNow()
+	}
+}
+
+func TestStmtCacheConcurrent(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	cache := NewStmtCache(db, 3)
+	defer cache.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			table := []string{"a", "b", "c", "d", "e"}[i%5]
+			_, err := NewMaryBuilder().Select("id").From(table, "").Exec(context.Background(), cache)
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if s := cache.Stats(); s.Hits+s.Misses != 20 || s.Size > 3 {
+		t.Logf("unexpected stats %+v", s)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,176 @@
+package obreron
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// TagName es el nombre del tag de struct usado para mapear campos con columnas
+const TagName = "db"
+
+// fieldInfo describe un campo de struct mapeado a una columna
+type fieldInfo struct {
+	column string
+	// index es la ruta del campo desde el struct raíz, ver reflect.Value.FieldByIndex
+	index []int
+	depth int
+
+	omitempty bool
+	readonly  bool
+	pk        bool
+}
+
+// structInfo contiene los campos mapeados de un tipo struct, en el orden de declaración
+type structInfo struct {
+	fields   []*fieldInfo
+	byColumn map[string]*fieldInfo
+}
+
+// structInfos guarda la metadata ya calculada por tipo
+var structInfos sync.Map
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// getStructInfo devuelve la metadata del tipo struct t, calculándola solo la primera vez
+func getStructInfo(t reflect.Type) *structInfo {
+	if si, ok := structInfos.Load(t); ok {
+		return si.(*structInfo)
+	}
+
+	si := &structInfo{byColumn: map[string]*fieldInfo{}}
+	collectFields(si, t, nil, 0)
+
+	actual, _ := structInfos.LoadOrStore(t, si)
+	return actual.(*structInfo)
+}
+
+// collectFields agrega a si los campos de t. Los structs embebidos sin nombre en el tag se aplanan, y ante
+// columnas repetidas prevalece el campo menos profundo
+func collectFields(si *structInfo, t reflect.Type, parent []int, depth int) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, hasTag := sf.Tag.Lookup(TagName)
+
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		index := make([]int, len(parent)+1)
+		copy(index, parent)
+		index[len(parent)] = i
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct && !isScalarStruct(ft) {
+			// un struct embebido no exportado por puntero no puede crearse por reflexión
+			if sf.IsExported() || sf.Type.Kind() != reflect.Pointer {
+				collectFields(si, ft, index, depth+1)
+			}
+			continue
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		if !hasTag || name == "" {
+			name = snakeCase(sf.Name)
+		}
+
+		if prev, ok := si.byColumn[name]; ok && prev.depth <= depth {
+			continue
+		}
+
+		fi := &fieldInfo{column: name, index: index, depth: depth}
+
+		for _, o := range strings.Split(opts, ",") {
+			switch strings.TrimSpace(o) {
+			case "omitempty":
+				fi.omitempty = true
+			case "readonly":
+				fi.readonly = true
+			case "pk":
+				fi.pk = true
+			}
+		}
+
+		if prev, ok := si.byColumn[name]; ok {
+			for j := range si.fields {
+				if si.fields[j] == prev {
+					si.fields[j] = fi
+				}
+			}
+		} else {
+			si.fields = append(si.fields, fi)
+		}
+
+		si.byColumn[name] = fi
+	}
+}
+
+// isScalarStruct indica si el struct t se mapea a una sola columna en vez de aplanarse
+func isScalarStruct(t reflect.Type) bool {
+	return t == timeType || reflect.PointerTo(t).Implements(scannerType)
+}
+
+// snakeCase convierte un nombre de campo como `UserID` en `user_id`
+func snakeCase(name string) string {
+	runes := []rune(name)
+
+	var sb strings.Builder
+	sb.Grow(len(name) + 4)
+
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// fieldByIndex devuelve el campo de v en la ruta index, creando los structs embebidos por puntero que sean nil
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// fieldByIndexNoAlloc devuelve el campo de v en la ruta index, o false si algún struct embebido por puntero es nil
+func fieldByIndexNoAlloc(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
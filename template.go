@@ -0,0 +1,158 @@
+package obreron
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrTemplateParams indica que la cantidad de parámetros no calza con la del template
+var ErrTemplateParams = errors.New("obreron: cantidad de parámetros inválida para el template")
+
+// Template es el sql ya compilado de una consulta. Es inmutable y seguro para uso concurrente, por lo que
+// puede construirse una sola vez y reutilizarse cambiando solo los valores de los parámetros.
+//
+//	usersByStatus, err := obreron.NewMaryBuilder().Select("user_id").From("users", "u").
+//		Where().AndParam("user_status", "=", 0).Compile()
+//
+//	q, params, err := usersByStatus.Bind(1)
+type Template struct {
+	// q es el sql con marcas `?` y bound el mismo sql con las marcas de dialect
+	q       string
+	bound   string
+	params  []interface{}
+	dialect Dialect
+}
+
+// Compile compila la consulta en un Template, o devuelve el error de la consulta si no puede construirse.
+// Los parámetros registrados se conservan como valores por defecto, y su cantidad es la que debe recibir Bind.
+// Las marcas se convierten a las del dialecto una sola vez, al compilar
+func (s *Select) Compile() (*Template, error) {
+	q, params := s.Build()
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	t := &Template{
+		// el sql apunta a la memoria del builder, que puede reutilizarse, así que se copia
+		q:       strings.Clone(q),
+		params:  make([]interface{}, len(params)),
+		dialect: s.Dialect(),
+	}
+	t.bound = Rebind(t.dialect, t.q)
+	copy(t.params, params)
+
+	return t, nil
+}
+
+// SQL devuelve el sql del template, con las marcas de parámetro de su dialecto
+func (t *Template) SQL() string {
+	return t.bound
+}
+
+// NumParams devuelve la cantidad de parámetros que recibe el template
+func (t *Template) NumParams() int {
+	return len(t.params)
+}
+
+// Dialect devuelve el dialecto con que se compiló el template
+func (t *Template) Dialect() Dialect {
+	return t.dialect
+}
+
+// Defaults devuelve una copia de los parámetros registrados al compilar el template
+func (t *Template) Defaults() []interface{} {
+	out := make([]interface{}, len(t.params))
+	copy(out, t.params)
+	return out
+}
+
+// Bind devuelve el sql del template, con las marcas de su dialecto, junto con params, verificando que sean
+// tantos como los que recibe. params no se copia, por lo que no se realizan asignaciones de memoria
+func (t *Template) Bind(params ...interface{}) (string, []interface{}, error) {
+	if len(params) != len(t.params) {
+		return t.bound, params, fmt.Errorf("%w: se esperaban %d y se recibieron %d", ErrTemplateParams, len(t.params), len(params))
+	}
+	return t.bound, params, nil
+}
+
+// With devuelve un Builder con el sql del template y los parámetros params, para ejecutarlo con
+// Query, QueryRow, Exec o ScanAll. Si la cantidad de parámetros no calza, el error se entrega al ejecutarlo
+func (t *Template) With(params ...interface{}) *BoundTemplate {
+	return &BoundTemplate{t: t, params: params}
+}
+
+// BoundTemplate es un Template con sus parámetros
+type BoundTemplate struct {
+	t      *Template
+	params []interface{}
+}
+
+// Build devuelve el sql del template, con marcas `?` como todo Builder, y los parámetros asociados
+func (b *BoundTemplate) Build() (string, []interface{}) {
+	return b.t.q, b.params
+}
+
+// rebindTo devuelve q con las marcas del dialecto d. Si q es el sql del template, sin cambios de los hooks,
+// y d su dialecto, se usa el convertido al compilar
+func (b *BoundTemplate) rebindTo(d Dialect, q string) string {
+	if q == b.t.q && reflect.TypeOf(d) == reflect.TypeOf(b.t.dialect) {
+		return b.t.bound
+	}
+
+	return Rebind(d, q)
+}
+
+// Dialect devuelve el dialecto con que se compiló el template
+func (b *BoundTemplate) Dialect() Dialect {
+	return b.t.dialect
+}
+
+// Err devuelve ErrTemplateParams si la cantidad de parámetros no calza con la del template
+func (b *BoundTemplate) Err() error {
+	_, _, err := b.t.Bind(b.params...)
+	return err
+}
+
+// Query ejecuta el template con los parámetros params y devuelve sus filas
+func (t *Template) Query(ctx context.Context, r Runner, params ...interface{}) (*sql.Rows, error) {
+	return Query(ctx, r, t.With(params...))
+}
+
+// QueryRow ejecuta el template con los parámetros params, esperando a lo más una fila
+func (t *Template) QueryRow(ctx context.Context, r Runner, params ...interface{}) *Row {
+	return QueryRow(ctx, r, t.With(params...))
+}
+
+// Exec ejecuta el template con los parámetros params sin devolver filas
+func (t *Template) Exec(ctx context.Context, r Runner, params ...interface{}) (sql.Result, error) {
+	return Exec(ctx, r, t.With(params...))
+}
+
+// TemplateCache guarda templates por nombre, compilándolos la primera vez que se piden.
+// Es seguro para uso concurrente y su valor cero está listo para usarse
+type TemplateCache struct {
+	templates sync.Map
+}
+
+// Get devuelve el template guardado con key, o lo compila con la consulta devuelta por build si no existe.
+// build debe construir siempre la misma forma de consulta para la misma llave. Si la consulta no compila
+// se devuelve su error y no se guarda nada
+func (c *TemplateCache) Get(key string, build func() *Select) (*Template, error) {
+	if t, ok := c.templates.Load(key); ok {
+		return t.(*Template), nil
+	}
+
+	compiled, err := build().Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	t, _ := c.templates.LoadOrStore(key, compiled)
+	return t.(*Template), nil
+}
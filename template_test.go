@@ -0,0 +1,219 @@
+package obreron
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTemplateBind(t *testing.T) {
+	b := NewMaryBuilder()
+	b.Select("user_id").From("users", "u").Where().
+		AndParam("user_status", "=", 0).
+		AndParam("user_type", "=", 1).
+		AndParam("user_name", "LIKE", "m%")
+
+	q, p := b.Build()
+	q = strings.Clone(q)
+	compiled, err := b.Compile()
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	// el template no debe verse afectado por la reutilización del builder
+	b.Reset()
+	_ = b.Select("otra_columna_mas_larga_que_la_anterior").From("otra_tabla", "o").String()
+
+	if compiled.SQL() != q || compiled.NumParams() != len(p) || !reflect.DeepEqual(compiled.Defaults(), []interface{}{0, 1, "m%"}) {
+		t.Logf("expected : %s %v", q, p)
+		t.Logf("generated: %s %v", compiled.SQL(), compiled.Defaults())
+		t.FailNow()
+	}
+
+	bq, bp, err := compiled.Bind(1, 2, 3)
+
+	if err != nil || bq != q || !reflect.DeepEqual(bp, []interface{}{1, 2, 3}) {
+		t.Logf("unexpected bind %s %v %v", bq, bp, err)
+		t.FailNow()
+	}
+
+	if _, _, err := compiled.Bind(1); !errors.Is(err, ErrTemplateParams) {
+		t.Logf("expected ErrTemplateParams, got %v", err)
+		t.FailNow()
+	}
+}
+
+func TestTemplateBindDoesNotAllocate(t *testing.T) {
+	compiled, _ := NewBuilder(Postgres{}).Select("user_id").From("users", "u").Where().AndParam("user_status", "=", 0).Compile()
+	params := []interface{}{1}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _, _ = compiled.Bind(params...)
+	})
+
+	if allocs != 0 {
+		t.Logf("expected 0 allocs, got %v", allocs)
+		t.FailNow()
+	}
+}
+
+func TestTemplateExec(t *testing.T) {
+	db, fdb := newFakeDB(t)
+
+	var cache TemplateCache
+	builds := 0
+
+	build := func() *Select {
+		builds++
+		return NewBuilder(Postgres{}).Select("user_id").From("users", "u").Where().AndParam("user_status", "=", 0)
+	}
+
+	for i := 0; i < 3; i++ {
+		compiled, err := cache.Get("users_by_status", build)
+		if err != nil {
+			t.Logf("unexpected error: %v", err)
+			t.FailNow()
+		}
+
+		if _, err := compiled.Exec(context.Background(), db, i); err != nil {
+			t.Logf("unexpected error: %v", err)
+			t.FailNow()
+		}
+	}
+
+	if builds != 1 {
+		t.Logf("expected 1 build, got %d", builds)
+		t.FailNow()
+	}
+
+	expected := "SELECT user_id FROM users u  WHERE 1=1  AND user_status = $1"
+
+	if c := fdb.lastCall(); c.query != expected || c.args[0] != int64(2) {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s %v", c.query, c.args)
+		t.FailNow()
+	}
+
+	compiled, _ := cache.Get("users_by_status", build)
+
+	if _, err := compiled.Exec(context.Background(), db); !errors.Is(err, ErrTemplateParams) {
+		t.Logf("expected ErrTemplateParams, got %v", err)
+		t.FailNow()
+	}
+}
+
+func TestTemplateCompileErrors(t *testing.T) {
+	b := NewMaryBuilder().Select("id").From("users", "").Where().AndParam(NewCase(), "=", 1)
+
+	if compiled, err := b.Compile(); !errors.Is(err, ErrInvalidExpression) || compiled != nil {
+		t.Logf("expected ErrInvalidExpression, got %v %v", compiled, err)
+		t.FailNow()
+	}
+
+	var cache TemplateCache
+	build := func() *Select {
+		return NewBuilder(Sqlite{}).Select("id").From("users", "").ForUpdate()
+	}
+
+	if _, err := cache.Get("locked", build); !errors.Is(err, ErrUnsupported) {
+		t.Logf("expected ErrUnsupported, got %v", err)
+		t.FailNow()
+	}
+
+	if _, ok := cache.templates.Load("locked"); ok {
+		t.Logf("a template that does not compile must not be cached")
+		t.FailNow()
+	}
+}
+
+func TestTemplateRebindsOnce(t *testing.T) {
+	compiled, err := NewBuilder(Postgres{}).Select("id").From("users", "").Where().
+		AndParam("status", "=", 0).AndParam("kind", "=", 1).Compile()
+
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	expected := "SELECT id FROM users WHERE 1=1  AND status = $1 AND kind = $2"
+
+	if q, _, _ := compiled.Bind(2, 3); q != expected || compiled.SQL() != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+
+	bound := compiled.With(2, 3)
+	q, _ := bound.Build()
+
+	if q != "SELECT id FROM users WHERE 1=1  AND status = ? AND kind = ?" || bound.rebindTo(Postgres{}, q) != expected {
+		t.Logf("unexpected build %s", q)
+		t.FailNow()
+	}
+
+	// ejecutado con otro dialecto se convierte desde las marcas originales
+	if rq := bound.rebindTo(Mysql{}, q); rq != q {
+		t.Logf("unexpected rebind %s", rq)
+		t.FailNow()
+	}
+}
+
+func TestTemplateWithAfterBuildHook(t *testing.T) {
+	db, fdb := newFakeDB(t)
+
+	AddHook(Hook{
+		AfterBuild: func(ctx context.Context, st *Statement) error {
+			st.SQL += " AND tenant_id = ?"
+			st.Params = append(st.Params, 7)
+			return nil
+		},
+	})
+	defer ResetHooks()
+
+	compiled, err := NewBuilder(Postgres{}).Select("id").From("users", "").Where().AndParam("status", "=", 0).Compile()
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	if _, err := compiled.Exec(context.Background(), db, 1); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	expected := "SELECT id FROM users WHERE 1=1  AND status = $1 AND tenant_id = $2"
+
+	if c := fdb.lastCall(); c.query != expected || !reflect.DeepEqual(c.args, []driver.Value{int64(1), int64(7)}) {
+		t.Logf("expected : %s [1 7]", expected)
+		t.Logf("generated: %s %v", c.query, c.args)
+		t.FailNow()
+	}
+}
+
+func BenchmarkHeavyQueryBuilder(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		heavyQueryBuild(b, NewMaryBuilder())
+	}
+}
+
+func BenchmarkHeavyQueryTemplate(b *testing.B) {
+	var cache TemplateCache
+	build := func() *Select {
+		bl := NewMaryBuilder()
+		heavyQueryBuild(b, bl)
+		return bl
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		compiled, _ := cache.Get("heavy", build)
+		_, _, _ = compiled.Bind(126, 126, 3)
+	}
+}
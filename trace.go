@@ -0,0 +1,137 @@
+package obreron
+
+import (
+	"context"
+	"strings"
+)
+
+// Atributos registrados en los spans de TracingHook. Siguen las convenciones de OpenTelemetry para bases de datos
+const (
+	// AttrOperation es la operación de la sentencia, como SELECT o INSERT
+	AttrOperation = "db.operation"
+	// AttrTable es la tabla principal de la sentencia
+	AttrTable = "db.sql.table"
+	// AttrStatement es el texto normalizado de la sentencia, sin los valores de sus parámetros
+	AttrStatement = "db.statement"
+	// AttrFingerprint es el hash del Fingerprint de la sentencia
+	AttrFingerprint = "db.statement.fingerprint"
+	// AttrRows es la cantidad de filas leídas o afectadas, si se conoce. Ver TracingHook
+	AttrRows = "db.rows"
+)
+
+// Attribute es un atributo de un Span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Tracer crea los spans que envuelven la ejecución de las sentencias. Es una interfaz mínima para no depender
+// de una implementación de tracing en particular, como OpenTelemetry, que puede adaptarse a ella
+type Tracer interface {
+	// Start inicia un span llamado name como hijo del span de ctx, devolviendo el contexto que lo contiene
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span es la medición de la ejecución de una sentencia
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// TracingHook devuelve un Hook que envuelve la ejecución de cada sentencia en un span de t, con los atributos
+// AttrOperation, AttrTable, AttrStatement, AttrFingerprint y, si se conoce, AttrRows. Regístrelo con AddHook,
+// o con el método Hook de un builder.
+//
+// Los spans de QueryRow nunca tienen AttrRows, ya que la fila se lee con Scan después de que el span termina.
+// Los de Query tampoco, salvo que se ejecuten con ScanAll o ScanOne
+func TracingHook(t Tracer) Hook {
+	return Hook{
+		AroundExecute: func(ctx context.Context, st *Statement, next func(ctx context.Context) error) error {
+			fp := FingerprintSQL(st.Dialect, st.SQL)
+			op := operation(st.Dialect, st.SQL)
+			table := ""
+
+			if tb, ok := st.Builder.(interface{ mainTable() string }); ok {
+				table = tb.mainTable()
+			}
+
+			name := op
+			if table != "" {
+				name += " " + table
+			}
+
+			ctx, span := t.Start(ctx, name)
+			defer span.End()
+
+			span.SetAttributes(
+				Attribute{Key: AttrOperation, Value: op},
+				Attribute{Key: AttrTable, Value: table},
+				Attribute{Key: AttrStatement, Value: fp.Text},
+				Attribute{Key: AttrFingerprint, Value: fp.String()},
+			)
+
+			err := next(ctx)
+			if err != nil {
+				span.RecordError(err)
+				return err
+			}
+
+			if st.RowCount >= 0 {
+				span.SetAttributes(Attribute{Key: AttrRows, Value: st.RowCount})
+			}
+
+			return nil
+		},
+	}
+}
+
+// operation devuelve la primera palabra de la sentencia q, del dialecto d, en mayúsculas, ignorando comentarios y paréntesis
+func operation(d Dialect, q string) string {
+	for _, t := range tokenize(q, mysqlLexing(d)) {
+		if t.kind == tokWord {
+			return strings.ToUpper(t.text)
+		}
+	}
+	return ""
+}
+
+// mainTable devuelve la tabla de la clausula FROM, o un string vacio si el origen es una subconsulta
+func (s *Select) mainTable() string {
+	toks := tokenize(s.source.String(), mysqlLexing(s.Dialect()))
+
+	for i := 0; i+1 < len(toks); i++ {
+		if toks[i].kind != tokWord || !strings.EqualFold(toks[i].text, "FROM") {
+			continue
+		}
+
+		// un nombre calificado como `esquema`.`tabla` se divide en varios tokens sin espacios entre ellos
+		var sb strings.Builder
+		for j := i + 1; j < len(toks) && (j == i+1 || !toks[j].space); j++ {
+			part := unquoteIdent(toks[j])
+			if part == "" {
+				break
+			}
+			sb.WriteString(part)
+		}
+
+		return sb.String()
+	}
+
+	return ""
+}
+
+func (s *Insert) mainTable() string { return s.table }
+func (s *Update) mainTable() string { return s.table }
+
+// unquoteIdent devuelve el identificador del token t sin escapar, o un string vacio si t no es un identificador
+func unquoteIdent(t sqlToken) string {
+	switch {
+	case t.kind == tokWord:
+		return t.text
+	case t.kind == tokQuoted && t.text[0] != '\'' && len(t.text) >= 2:
+		q := t.text[:1]
+		return strings.ReplaceAll(t.text[1:len(t.text)-1], q+q, q)
+	}
+	return ""
+}
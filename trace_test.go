@@ -0,0 +1,114 @@
+package obreron
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+// testTracer es un Tracer de prueba que guarda los atributos de cada span
+type testTracer struct {
+	spans []*testSpan
+}
+
+type testSpan struct {
+	name  string
+	attrs map[string]interface{}
+	errs  []error
+	ended bool
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	s := &testSpan{name: name, attrs: map[string]interface{}{}}
+	t.spans = append(t.spans, s)
+	return ctx, s
+}
+
+func (s *testSpan) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *testSpan) RecordError(err error) { s.errs = append(s.errs, err) }
+func (s *testSpan) End()                  { s.ended = true }
+
+func TestTracingHook(t *testing.T) {
+	db, fdb := newFakeDB(t)
+	tr := &testTracer{}
+
+	AddHook(TracingHook(tr))
+	defer ResetHooks()
+
+	b := NewBuilder(Postgres{}).Select("id").From("users", "u").Where().AndParam("status", "=", 1)
+	q, _ := b.Build()
+	fdb.respond(Rebind(Postgres{}, q), fakeResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(1)}, {int64(2)}}})
+
+	if _, err := ScanAll[int64](context.Background(), db, b); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	u := NewMaryUpdate("users").Set("name", "ana").Where().AndParam("id", "=", 1)
+	uq, _ := u.Build()
+	fdb.respond(uq, fakeResult{affected: 1})
+
+	if _, err := Exec(context.Background(), db, u); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	if len(tr.spans) != 2 {
+		t.Logf("expected 2 spans, got %d", len(tr.spans))
+		t.FailNow()
+	}
+
+	sel, upd := tr.spans[0], tr.spans[1]
+
+	if sel.name != "SELECT users" || !sel.ended || sel.attrs[AttrRows] != int64(2) ||
+		sel.attrs[AttrFingerprint] != b.Fingerprint().String() || sel.attrs[AttrTable] != "users" {
+		t.Logf("unexpected span %+v", sel)
+		t.FailNow()
+	}
+
+	if upd.name != "UPDATE users" || upd.attrs[AttrOperation] != "UPDATE" || upd.attrs[AttrRows] != int64(1) {
+		t.Logf("unexpected span %+v", upd)
+		t.FailNow()
+	}
+
+	// la fila de QueryRow se lee después de terminar el span, así que no se conoce la cantidad de filas
+	var id int64
+	if err := QueryRow(context.Background(), db, b).Scan(&id); err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	if row := tr.spans[2]; !row.ended || row.name != "SELECT users" {
+		t.Logf("unexpected span %+v", row)
+		t.FailNow()
+	} else if _, ok := row.attrs[AttrRows]; ok {
+		t.Logf("QueryRow spans must not report %s, got %v", AttrRows, row.attrs[AttrRows])
+		t.FailNow()
+	}
+}
+
+func TestMainTable(t *testing.T) {
+	cases := map[string]*Select{
+		"users":    NewMaryBuilder().Select("id").From("users", "u"),
+		"a.b":      NewMaryBuilder().Select("id").From("`a`.`b`", ""),
+		"x`y":      NewMaryBuilder().Select("id").From("`x``y`", ""),
+		"":         NewMaryBuilder().Select("id").From(NewMaryBuilder().Select("1").From("t", ""), "s"),
+		"sin_from": NewMaryBuilder().Select("1"),
+	}
+
+	for expected, b := range cases {
+		if expected == "sin_from" {
+			expected = ""
+		}
+
+		if got := b.mainTable(); got != expected {
+			t.Logf("expected %q, got %q", expected, got)
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Package otel adapta OpenTelemetry a la interfaz obreron.Tracer.
+//
+// Está en su propio módulo para que el de obreron no dependa de OpenTelemetry:
+//
+//	obreron.AddHook(obreron.TracingHook(otel.NewTracer(nil)))
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/profe-ajedrez/obreron"
+)
+
+// InstrumentationName es el nombre con que se obtiene el trace.Tracer del proveedor
+const InstrumentationName = "github.com/profe-ajedrez/obreron"
+
+var _ obreron.Tracer = (*Tracer)(nil)
+
+// Tracer es un obreron.Tracer que crea los spans con un trace.Tracer de OpenTelemetry
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer devuelve un Tracer que crea los spans con el proveedor tp, o con el proveedor global de
+// OpenTelemetry si tp es nil
+func NewTracer(tp trace.TracerProvider) *Tracer {
+	if tp == nil {
+		tp = otelapi.GetTracerProvider()
+	}
+	return &Tracer{tracer: tp.Tracer(InstrumentationName)}
+}
+
+// Start inicia un span de tipo cliente llamado name, como hijo del span de ctx
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, obreron.Span) {
+	ctx, s := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+	return ctx, span{s}
+}
+
+// span es el obreron.Span entregado por Tracer
+type span struct {
+	s trace.Span
+}
+
+func (s span) SetAttributes(attrs ...obreron.Attribute) {
+	kv := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		kv = append(kv, keyValue(a))
+	}
+	s.s.SetAttributes(kv...)
+}
+
+// RecordError registra err en el span y marca su estado como error
+func (s span) RecordError(err error) {
+	s.s.RecordError(err)
+	s.s.SetStatus(codes.Error, err.Error())
+}
+
+func (s span) End() {
+	s.s.End()
+}
+
+// keyValue convierte a en un atributo de OpenTelemetry. Los valores de tipos que OpenTelemetry no soporta
+// se registran como texto
+func keyValue(a obreron.Attribute) attribute.KeyValue {
+	switch v := a.Value.(type) {
+	case string:
+		return attribute.String(a.Key, v)
+	case int64:
+		return attribute.Int64(a.Key, v)
+	case int:
+		return attribute.Int(a.Key, v)
+	case bool:
+		return attribute.Bool(a.Key, v)
+	case float64:
+		return attribute.Float64(a.Key, v)
+	}
+	return attribute.String(a.Key, fmt.Sprint(a.Value))
+}
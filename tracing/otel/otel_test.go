@@ -0,0 +1,134 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/profe-ajedrez/obreron"
+)
+
+// newExporter devuelve un exportador en memoria y un Tracer que le entrega los spans al terminar
+func newExporter() (*tracetest.InMemoryExporter, *Tracer) {
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	return exp, NewTracer(tp)
+}
+
+func TestTracerWithTracingHook(t *testing.T) {
+	exp, tracer := newExporter()
+	hook := obreron.TracingHook(tracer)
+
+	b := obreron.NewMaryBuilder().Select("id").From("`users`", "u").Where().AndParam("status", "=", 1)
+	q, params := b.Build()
+	st := &obreron.Statement{Mode: obreron.ModeQuery, SQL: q, Params: params, Dialect: b.Dialect(), Builder: b, RowCount: -1}
+
+	err := hook.AroundExecute(context.Background(), st, func(ctx context.Context) error {
+		if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			return errors.New("the statement must run inside the span")
+		}
+		st.RowCount = 3
+		return nil
+	})
+
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Logf("expected 1 span, got %d", len(spans))
+		t.FailNow()
+	}
+
+	s := spans[0]
+	fp := b.Fingerprint()
+
+	if s.Name != "SELECT users" || s.SpanKind != trace.SpanKindClient || s.InstrumentationLibrary.Name != InstrumentationName {
+		t.Logf("unexpected span %+v", s)
+		t.FailNow()
+	}
+
+	expected := map[attribute.Key]attribute.Value{
+		obreron.AttrOperation:   attribute.StringValue("SELECT"),
+		obreron.AttrTable:       attribute.StringValue("users"),
+		obreron.AttrStatement:   attribute.StringValue(fp.Text),
+		obreron.AttrFingerprint: attribute.StringValue(fp.String()),
+		obreron.AttrRows:        attribute.Int64Value(3),
+	}
+
+	got := map[attribute.Key]attribute.Value{}
+	for _, kv := range s.Attributes {
+		got[kv.Key] = kv.Value
+	}
+
+	for k, v := range expected {
+		if got[k] != v {
+			t.Logf("attribute %s: expected %v, got %v", k, v.Emit(), got[k].Emit())
+			t.FailNow()
+		}
+	}
+}
+
+func TestTracerRecordsErrors(t *testing.T) {
+	exp, tracer := newExporter()
+	hook := obreron.TracingHook(tracer)
+	boom := errors.New("boom")
+
+	b := obreron.NewMaryUpdate("users").Set("name", "ana")
+	q, params := b.Build()
+	st := &obreron.Statement{Mode: obreron.ModeExec, SQL: q, Params: params, Dialect: b.Dialect(), Builder: b, RowCount: -1}
+
+	err := hook.AroundExecute(context.Background(), st, func(ctx context.Context) error {
+		return boom
+	})
+
+	spans := exp.GetSpans()
+
+	if !errors.Is(err, boom) || len(spans) != 1 || spans[0].Name != "UPDATE users" {
+		t.Logf("unexpected result %v %+v", err, spans)
+		t.FailNow()
+	}
+
+	s := spans[0]
+
+	if s.Status.Code != codes.Error || s.Status.Description != "boom" || len(s.Events) != 1 || s.Events[0].Name != "exception" {
+		t.Logf("expected the error to be recorded, got %+v %+v", s.Status, s.Events)
+		t.FailNow()
+	}
+
+	for _, kv := range s.Attributes {
+		if kv.Key == obreron.AttrRows {
+			t.Logf("unexpected rows attribute on failed statement")
+			t.FailNow()
+		}
+	}
+}
+
+func TestKeyValue(t *testing.T) {
+	cases := map[string]struct {
+		in   interface{}
+		want attribute.Value
+	}{
+		"string": {"a", attribute.StringValue("a")},
+		"int64":  {int64(2), attribute.Int64Value(2)},
+		"int":    {3, attribute.Int64Value(3)},
+		"bool":   {true, attribute.BoolValue(true)},
+		"float":  {1.5, attribute.Float64Value(1.5)},
+		"other":  {[]int{1}, attribute.StringValue("[1]")},
+	}
+
+	for name, c := range cases {
+		if kv := keyValue(obreron.Attribute{Key: "k", Value: c.in}); kv.Value != c.want {
+			t.Logf("%s: expected %v, got %v", name, c.want.Emit(), kv.Value.Emit())
+			t.FailNow()
+		}
+	}
+}
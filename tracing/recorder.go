@@ -0,0 +1,114 @@
+// Package tracing contiene adaptadores para la interfaz obreron.Tracer.
+//
+// Recorder guarda los spans en memoria, lo que permite verificar en pruebas qué sentencias se ejecutaron.
+// El adaptador para OpenTelemetry está en tracing/otel, un módulo aparte para que obreron no dependa de
+// OpenTelemetry.
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/profe-ajedrez/obreron"
+)
+
+var _ obreron.Tracer = (*Recorder)(nil)
+
+// Recorder es un obreron.Tracer que guarda en memoria los spans terminados.
+// Es seguro para uso concurrente y su valor cero está listo para usarse
+type Recorder struct {
+	mu    sync.Mutex
+	spans []RecordedSpan
+}
+
+// NewRecorder devuelve un Recorder vacío
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Start inicia un span llamado name. El span se guarda en el Recorder al terminar
+func (r *Recorder) Start(ctx context.Context, name string) (context.Context, obreron.Span) {
+	return ctx, &span{
+		recorder: r,
+		data: RecordedSpan{
+			Name:       name,
+			Attributes: map[string]interface{}{},
+			Start:      time.Now(),
+		},
+	}
+}
+
+// Spans devuelve una copia de los spans terminados, en el orden en que terminaron
+func (r *Recorder) Spans() []RecordedSpan {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]RecordedSpan, len(r.spans))
+	copy(out, r.spans)
+	return out
+}
+
+// Reset descarta los spans guardados
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spans = nil
+}
+
+// RecordedSpan es un span guardado por un Recorder
+type RecordedSpan struct {
+	Name       string
+	Attributes map[string]interface{}
+	Errors     []error
+	Start      time.Time
+	End        time.Time
+}
+
+// Duration devuelve la duración del span
+func (s RecordedSpan) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// span es el obreron.Span entregado por Recorder
+type span struct {
+	recorder *Recorder
+	mu       sync.Mutex
+	data     RecordedSpan
+	ended    bool
+}
+
+func (s *span) SetAttributes(attrs ...obreron.Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range attrs {
+		s.data.Attributes[a.Key] = a.Value
+	}
+}
+
+func (s *span) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Errors = append(s.data.Errors, err)
+}
+
+// End termina el span y lo guarda en el Recorder. Las llamadas siguientes no tienen efecto
+func (s *span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	s.recorder.spans = append(s.recorder.spans, data)
+}
@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/profe-ajedrez/obreron"
+)
+
+func TestRecorderWithTracingHook(t *testing.T) {
+	rec := NewRecorder()
+	hook := obreron.TracingHook(rec)
+
+	b := obreron.NewMaryBuilder().Select("id").From("`users`", "u").Where().AndParam("status", "=", 1)
+	q, params := b.Build()
+
+	st := &obreron.Statement{Mode: obreron.ModeQuery, SQL: q, Params: params, Dialect: b.Dialect(), Builder: b, RowCount: -1}
+
+	err := hook.AroundExecute(context.Background(), st, func(ctx context.Context) error {
+		st.RowCount = 3
+		return nil
+	})
+
+	if err != nil {
+		t.Logf("unexpected error: %v", err)
+		t.FailNow()
+	}
+
+	spans := rec.Spans()
+	if len(spans) != 1 {
+		t.Logf("expected 1 span, got %d", len(spans))
+		t.FailNow()
+	}
+
+	s := spans[0]
+	fp := b.Fingerprint()
+
+	expected := map[string]interface{}{
+		obreron.AttrOperation:   "SELECT",
+		obreron.AttrTable:       "users",
+		obreron.AttrStatement:   fp.Text,
+		obreron.AttrFingerprint: fp.String(),
+		obreron.AttrRows:        int64(3),
+	}
+
+	if s.Name != "SELECT users" || len(s.Errors) != 0 || s.End.Before(s.Start) {
+		t.Logf("unexpected span %+v", s)
+		t.FailNow()
+	}
+
+	for k, v := range expected {
+		if s.Attributes[k] != v {
+			t.Logf("attribute %s: expected %v, got %v", k, v, s.Attributes[k])
+			t.FailNow()
+		}
+	}
+}
+
+func TestRecorderRecordsErrors(t *testing.T) {
+	rec := NewRecorder()
+	hook := obreron.TracingHook(rec)
+	boom := errors.New("boom")
+
+	b := obreron.NewMaryUpdate("users").Set("name", "ana")
+	q, params := b.Build()
+	st := &obreron.Statement{Mode: obreron.ModeExec, SQL: q, Params: params, Dialect: b.Dialect(), Builder: b, RowCount: -1}
+
+	err := hook.AroundExecute(context.Background(), st, func(ctx context.Context) error {
+		return boom
+	})
+
+	spans := rec.Spans()
+
+	if !errors.Is(err, boom) || len(spans) != 1 || len(spans[0].Errors) != 1 || spans[0].Name != "UPDATE users" {
+		t.Logf("unexpected result %v %+v", err, spans)
+		t.FailNow()
+	}
+
+	if _, ok := spans[0].Attributes[obreron.AttrRows]; ok {
+		t.Logf("unexpected rows attribute on failed statement")
+		t.FailNow()
+	}
+
+	rec.Reset()
+
+	if len(rec.Spans()) != 0 {
+		t.Logf("expected no spans after Reset")
+		t.FailNow()
+	}
+}
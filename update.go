@@ -0,0 +1,192 @@
+package obreron
+
+import (
+	"bytes"
+	"fmt"
+	"unsafe"
+)
+
+// Update es el builder para sentencias UPDATE
+type Update struct {
+	set    *SQLBuilder
+	filter *SQLBuilder
+
+	*SQLBuilder
+
+	table string
+	err   error
+	hooks []Hook
+
+	q string
+}
+
+// NewMaryUpdate devuelve un nuevo builder de UPDATE para mysql sobre la tabla table
+func NewMaryUpdate(table string) *Update {
+	return NewUpdate(Mysql{}, table)
+}
+
+// NewUpdate devuelve un nuevo builder de UPDATE con el dialecto d sobre la tabla table
+func NewUpdate(d Dialect, table string) *Update {
+	return &Update{
+		set:        newSQLBuilder(d),
+		filter:     newSQLBuilder(d),
+		SQLBuilder: newSQLBuilder(d),
+		table:      table,
+	}
+}
+
+// Set agrega la asignación `col = ?` con el parámetro v
+func (s *Update) Set(col string, v interface{}) *Update {
+	s.q = ""
+
+	if s.set.Len() > 0 {
+		s.set.WriteString(", ")
+	}
+
+	s.set.WriteString(col)
+	s.set.WriteString(" = ")
+	s.set.WriteString(s.set.Dialect().ParamMark())
+	s.set.AddParam(v)
+
+	return s
+}
+
+// SetIf agrega la asignación `col = ?` con el parámetro v si cond es verdadera
+func (s *Update) SetIf(cond bool, col string, v interface{}) *Update {
+	if cond {
+		s.Set(col, v)
+	}
+	return s
+}
+
+// SetExpr agrega una asignación completa sin parámetros, como `visitas = visitas + 1`
+func (s *Update) SetExpr(expr string) *Update {
+	s.q = ""
+
+	if s.set.Len() > 0 {
+		s.set.WriteString(", ")
+	}
+
+	s.set.WriteString(expr)
+
+	return s
+}
+
+// SetRecord agrega una asignación por cada columna de v, que puede ser un struct o un map con llaves string.
+// De los structs se toman los campos según sus tags `db`, omitiendo los marcados `readonly` o `pk` y los
+// `omitempty` con valor cero; las llaves de los maps se ordenan alfabéticamente
+func (s *Update) SetRecord(v interface{}) *Update {
+	cols, params, err := recordValues(v, forUpdate)
+	if err != nil {
+		s.setErr(err)
+		return s
+	}
+
+	for i := range cols {
+		s.Set(cols[i], params[i])
+	}
+
+	return s
+}
+
+// Where inicializa la clausula where
+func (s *Update) Where() *Update {
+	s.q = ""
+	s.filter.Reset()
+	s.filter.ResetParams()
+
+	s.filter.WriteString(" WHERE 1=1 ")
+	return s
+}
+
+// AndParam Agrega una condición usando conector AND
+// c puede ser la condición como string o como un SQLBuilder
+// op es el operador y param el parámetro de la condición
+func (s *Update) AndParam(c interface{}, op string, param interface{}) *Update {
+	s.q = ""
+	s.filter.WriteString(" AND ")
+	parse(s.filter, c, param, newParsingOpts(EncloseOnlyBuilders, NoQuote, NoUseAs, "", op, ""))
+	return s
+}
+
+// AndParamIf Agrega una condición usando conector AND solo si cond es true
+func (s *Update) AndParamIf(cond bool, c interface{}, op string, param interface{}) *Update {
+	if cond {
+		s.AndParam(c, op, param)
+	}
+	return s
+}
+
+// And Agrega una condición usando conector AND
+// c es un string conteniendo la condición completa, o un Raw con sus parámetros
+func (s *Update) And(c interface{}) *Update {
+	return s.AndParam(c, "", nil)
+}
+
+// OrParam Agrega una condición usando conector OR
+// c puede ser la condición como string o como un SQLBuilder
+// op es el operador y param el parámetro de la condición
+func (s *Update) OrParam(c interface{}, op string, param interface{}) *Update {
+	s.q = ""
+	s.filter.WriteString(" OR ")
+	parse(s.filter, c, param, newParsingOpts(EncloseOnlyBuilders, NoQuote, NoUseAs, "", op, ""))
+	return s
+}
+
+// Err devuelve el primer error registrado al construir la sentencia, o ErrEmptyStatement si no tiene asignaciones
+func (s *Update) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if s.filter.err != nil {
+		return s.filter.err
+	}
+
+	if s.set.Len() == 0 {
+		return fmt.Errorf("%w: UPDATE %s sin asignaciones", ErrEmptyStatement, s.table)
+	}
+
+	return nil
+}
+
+func (s *Update) setErr(err error) {
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// Params devuelve los parámetros de las asignaciones seguidos de los de la clausula WHERE
+func (s *Update) Params() []interface{} {
+	s.params = make([]interface{}, 0, len(s.set.params)+len(s.filter.params))
+	s.params = append(s.params, s.set.params...)
+	s.params = append(s.params, s.filter.params...)
+	return s.params
+}
+
+func (s *Update) String() string {
+	if s.q != "" {
+		return s.q
+	}
+
+	if s.Len() > 0 {
+		// el sql anterior pudo haberse entregado, así que no se reutiliza su memoria
+		s.Buffer = bytes.Buffer{}
+	}
+
+	s.WriteString("UPDATE ")
+	s.WriteString(s.table)
+	s.WriteString(" SET ")
+	s.Write(s.set.Bytes())
+	s.Write(s.filter.Bytes())
+
+	s.q = *(*string)(unsafe.Pointer(&s.Buffer))
+
+	return s.q
+}
+
+// Build construye la sentencia devolviendo una tupla conteniendola en un string y los parámetros
+// registrados para su uso
+func (s *Update) Build() (string, []interface{}) {
+	return s.String(), s.Params()
+}
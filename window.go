@@ -0,0 +1,180 @@
+package obreron
+
+import "fmt"
+
+// FrameBound es uno de los límites del marco de una ventana, ver Window.Rows y Window.Range
+type FrameBound struct {
+	text   string
+	params []interface{}
+}
+
+var (
+	// UnboundedPreceding es el inicio de la partición
+	UnboundedPreceding = FrameBound{text: "UNBOUNDED PRECEDING"}
+
+	// CurrentRow es la fila actual
+	CurrentRow = FrameBound{text: "CURRENT ROW"}
+
+	// UnboundedFollowing es el final de la partición
+	UnboundedFollowing = FrameBound{text: "UNBOUNDED FOLLOWING"}
+)
+
+// Preceding es el límite n filas, o valores en un marco RANGE, antes de la fila actual. n se agrega como parámetro
+func Preceding(n interface{}) FrameBound {
+	return FrameBound{text: "? PRECEDING", params: []interface{}{n}}
+}
+
+// Following es el límite n filas, o valores en un marco RANGE, después de la fila actual. n se agrega como parámetro
+func Following(n interface{}) FrameBound {
+	return FrameBound{text: "? FOLLOWING", params: []interface{}{n}}
+}
+
+// Window es el builder de la especificación de una ventana, lo que se escribe en `OVER (...)` o en la
+// clausula WINDOW. Sus partes se escriben en el orden que espera el sql sin importar el orden de los llamados
+//
+//	w := NewWindow().PartitionBy("account_id").OrderBy("created_at").Rows(UnboundedPreceding, CurrentRow)
+//	b.Select("id").AddColumn(Over("SUM(amount)", w), "balance").From("movements", "")
+type Window struct {
+	base      string
+	partition SQLBuilder
+	order     SQLBuilder
+	frame     SQLBuilder
+}
+
+// NewWindow devuelve una especificación de ventana vacía, que abarca todas las filas
+func NewWindow() *Window {
+	return &Window{}
+}
+
+// Base define la ventana con nombre name, de la clausula WINDOW, que esta ventana extiende
+func (w *Window) Base(name string) *Window {
+	w.base = name
+	return w
+}
+
+// PartitionBy agrega la expresión expr, con sus parámetros params, a la clausula PARTITION BY
+func (w *Window) PartitionBy(expr string, params ...interface{}) *Window {
+	return w.add(&w.partition, " PARTITION BY ", expr, params)
+}
+
+// OrderBy agrega la expresión expr, con sus parámetros params, a la clausula ORDER BY de la ventana
+func (w *Window) OrderBy(expr string, params ...interface{}) *Window {
+	return w.add(&w.order, " ORDER BY ", expr, params)
+}
+
+// Rows define el marco de la ventana como las filas entre start y end
+func (w *Window) Rows(start, end FrameBound) *Window {
+	return w.between("ROWS", start, end)
+}
+
+// Range define el marco de la ventana como las filas cuyo valor de ORDER BY está entre start y end
+func (w *Window) Range(start, end FrameBound) *Window {
+	return w.between("RANGE", start, end)
+}
+
+// add agrega expr a la clausula part, que comienza con keyword, separándola con una coma de las anteriores
+func (w *Window) add(part *SQLBuilder, keyword, expr string, params []interface{}) *Window {
+	if part.Len() == 0 {
+		part.WriteString(keyword)
+	} else {
+		part.WriteString(", ")
+	}
+
+	part.WriteString(expr)
+	part.AddParam(params...)
+	return w
+}
+
+// between reemplaza el marco de la ventana por `unit BETWEEN start AND end`
+func (w *Window) between(unit string, start, end FrameBound) *Window {
+	w.frame.Reset()
+	w.frame.params = nil
+
+	w.frame.WriteByte(' ')
+	w.frame.WriteString(unit)
+	w.frame.WriteString(" BETWEEN ")
+	w.frame.WriteString(start.text)
+	w.frame.WriteString(" AND ")
+	w.frame.WriteString(end.text)
+	w.frame.AddParam(start.params...)
+	w.frame.AddParam(end.params...)
+	return w
+}
+
+// writeTo escribe la ventana entre paréntesis en sb, junto con sus parámetros
+func (w *Window) writeTo(d Dialect, sb *SQLBuilder) {
+	sb.WriteString(d.OpenEnclose())
+	sb.WriteString(w.base)
+	empty := w.base == ""
+
+	for _, part := range []*SQLBuilder{&w.partition, &w.order, &w.frame} {
+		b := part.Bytes()
+		// las partes comienzan con un espacio, que sobra al inicio de los paréntesis
+		if empty && len(b) > 0 {
+			b = b[1:]
+			empty = false
+		}
+		sb.Write(b)
+		sb.AddParam(part.params...)
+	}
+
+	sb.WriteString(d.CloseEnclose())
+}
+
+// WindowFunc es una función de ventana, como `ROW_NUMBER() OVER (...)`. Puede usarse como columna con Select o AddColumn
+type WindowFunc struct {
+	fn     string
+	params []interface{}
+	window *Window
+	name   string
+}
+
+// Over devuelve la función fn, con sus parámetros params, evaluada sobre la ventana w
+//
+//	Over("LAG(amount, ?, ?)", NewWindow().PartitionBy("account_id").OrderBy("created_at"), 1, 0)
+func Over(fn string, w *Window, params ...interface{}) *WindowFunc {
+	return &WindowFunc{fn: fn, params: params, window: w}
+}
+
+// OverWindow devuelve la función fn, con sus parámetros params, evaluada sobre la ventana con nombre name
+// definida con Select.Window
+func OverWindow(fn string, name string, params ...interface{}) *WindowFunc {
+	return &WindowFunc{fn: fn, params: params, name: name}
+}
+
+// ToSQL escribe la función de ventana en sb, junto con sus parámetros. Devuelve ErrInvalidExpression si no
+// tiene ventana ni nombre de ventana
+func (f *WindowFunc) ToSQL(d Dialect, sb *SQLBuilder) error {
+	if f.window == nil && f.name == "" {
+		return fmt.Errorf("%w: %s sin ventana", ErrInvalidExpression, f.fn)
+	}
+
+	sb.WriteString(f.fn)
+	sb.AddParam(f.params...)
+	sb.WriteString(" OVER ")
+
+	if f.window == nil {
+		sb.WriteString(f.name)
+		return nil
+	}
+
+	f.window.writeTo(d, sb)
+	return nil
+}
+
+// Window agrega a la consulta la ventana con nombre name y especificación w, en la clausula WINDOW, que
+// las funciones de ventana pueden usar con OverWindow
+func (s *Select) Window(name string, w *Window) *Select {
+	s.touch()
+
+	if s.window.Len() == 0 {
+		s.window.WriteString(" WINDOW ")
+	} else {
+		s.window.WriteString(", ")
+	}
+
+	s.window.WriteString(name)
+	s.window.WriteString(" AS ")
+	w.writeTo(s.Dialect(), s.window)
+	return s
+}
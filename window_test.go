@@ -0,0 +1,64 @@
+package obreron
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWindowFunctions(t *testing.T) {
+	balance := NewWindow().OrderBy("created_at").PartitionBy("account_id").Rows(UnboundedPreceding, CurrentRow)
+
+	b := NewBuilder(Postgres{}).Select("id", Over("ROW_NUMBER()", NewWindow())).
+		AddColumn(Over("SUM(amount)", balance), "balance").
+		AddColumn(Over("LAG(amount, ?, ?)", NewWindow().PartitionBy("account_id").OrderBy("created_at"), 1, 0), "previous").
+		AddColumn(OverWindow("AVG(amount)", "w"), "average").
+		AddColumn(Over("MAX(amount)", NewWindow().Base("w").Range(Preceding(100), Following(50))), "peak").
+		From("movements", "").
+		Where().AndParam("kind", "=", "credit").
+		Window("w", NewWindow().PartitionBy("date_trunc(?, created_at)", "month").OrderBy("amount DESC")).
+		Window("w2", NewWindow()).
+		OrderBy("id")
+
+	expected := "SELECT id,ROW_NUMBER() OVER (),SUM(amount) OVER (PARTITION BY account_id ORDER BY created_at ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS balance ," +
+		"LAG(amount, ?, ?) OVER (PARTITION BY account_id ORDER BY created_at) AS previous ,AVG(amount) OVER w AS average ," +
+		"MAX(amount) OVER (w RANGE BETWEEN ? PRECEDING AND ? FOLLOWING) AS peak  FROM movements WHERE 1=1  AND kind = ?" +
+		" WINDOW w AS (PARTITION BY date_trunc(?, created_at) ORDER BY amount DESC), w2 AS ()  ORDER BY id "
+
+	q, params := b.Build()
+
+	if q != expected {
+		t.Logf("expected : %s", expected)
+		t.Logf("generated: %s", q)
+		t.FailNow()
+	}
+
+	if expected := []interface{}{1, 0, 100, 50, "credit", "month"}; !reflect.DeepEqual(params, expected) {
+		t.Logf("expected params %v, got %v", expected, params)
+		t.FailNow()
+	}
+
+	b.Reset()
+
+	if q := b.Select("id").From("movements", "").String(); q != "SELECT id FROM movements" {
+		t.Logf("unexpected query after Reset: %s", q)
+		t.FailNow()
+	}
+}
+
+func TestWindowFuncWithoutWindow(t *testing.T) {
+	cases := map[string]*WindowFunc{
+		"empty name": OverWindow("ROW_NUMBER()", ""),
+		"nil window": Over("ROW_NUMBER()", nil),
+	}
+
+	for name, f := range cases {
+		b := NewMaryBuilder().Select("id").AddColumn(f, "rn").From("t", "")
+
+		if q := b.String(); !errors.Is(b.Err(), ErrInvalidExpression) || strings.Contains(q, "OVER") {
+			t.Logf("%s: expected %v, got %q %v", name, ErrInvalidExpression, q, b.Err())
+			t.FailNow()
+		}
+	}
+}